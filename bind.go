@@ -0,0 +1,215 @@
+package copier
+
+import (
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+var fileHeaderType = reflect.TypeOf(multipart.FileHeader{})
+
+// Bind copies multi-valued form data into a struct, fromValue may be a url.Values, an http.Header,
+// a *multipart.Form or a map[string][]string.
+//
+// Only fields explicitly bindable, tagged with `form:"name"` or `copier:"bind"` or listed in opt.Allow, are set.
+// Keys of other fields are ignored, or return ErrFieldNotAllowed in secure mode, with opt.Secure or a non-empty opt.Allow. Keys are matched with the form tag name, the copier tag name or the field name.
+// The first value is parsed into scalar fields, all values into slice fields.
+func Bind(toValue interface{}, fromValue interface{}, opt Option) error {
+	var (
+		values map[string][]string
+		files  map[string][]*multipart.FileHeader
+	)
+
+	switch from := fromValue.(type) {
+	case url.Values:
+		values = from
+	case http.Header:
+		values = from
+	case map[string][]string:
+		values = from
+	case *multipart.Form:
+		if from == nil {
+			return ErrInvalidCopyFrom
+		}
+		values, files = from.Value, from.File
+	default:
+		return fmt.Errorf("%w bind source %T", ErrNotSupported, fromValue)
+	}
+
+	to := indirect(reflect.ValueOf(toValue))
+	if !to.CanAddr() || to.Kind() != reflect.Struct {
+		return ErrInvalidCopyDestination
+	}
+
+	opt.WeaklyTyped, opt.TextMarshaling = true, true
+	converters := opt.converters()
+
+	var denied []string
+	for _, field := range bindFields(to.Type(), nil) {
+		name, bindable, ok := bindName(field)
+		if !ok {
+			continue
+		}
+
+		if !bindable && !opt.allowListed(field.Name) {
+			if opt.secure() && (len(lookupValues(values, name, opt.CaseSensitive)) > 0 || len(lookupFiles(files, name, opt.CaseSensitive)) > 0) {
+				denied = append(denied, field.Name)
+			}
+			continue
+		}
+
+		if fileType, _ := indirectType(field.Type); fileType == fileHeaderType {
+			if fileHeaders := lookupFiles(files, name, opt.CaseSensitive); len(fileHeaders) > 0 {
+				bindFiles(bindField(to, field.Index), fileHeaders)
+			}
+			continue
+		}
+
+		fieldValues := lookupValues(values, name, opt.CaseSensitive)
+		if len(fieldValues) == 0 {
+			continue
+		}
+
+		var from reflect.Value
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() != reflect.Uint8 {
+			from = reflect.ValueOf(fieldValues)
+		} else {
+			from = reflect.ValueOf(fieldValues[0])
+		}
+		if err := copyValue(bindField(to, field.Index), from, opt, converters); err != nil {
+			return withField(err, field.Name)
+		}
+	}
+
+	if len(denied) > 0 {
+		return &DeniedError{Fields: denied}
+	}
+	return nil
+}
+
+// bindFields returns the exported fields of the struct type t and of its embedded structs, with their index from t.
+// Promoted fields shadowed by a field of the same name are left out.
+func bindFields(t reflect.Type, index []int) []reflect.StructField {
+	if t, _ = indirectType(t); t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields, embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		field.Index = append(append([]int{}, index...), i)
+		fields = append(fields, field)
+		if field.Anonymous && indirectKind(field.Type) == reflect.Struct {
+			embedded = append(embedded, bindFields(field.Type, field.Index)...)
+		}
+	}
+
+	for _, field := range embedded {
+		if f, ok := t.FieldByName(field.Name); ok && len(f.Index) == len(field.Index)-len(index) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// bindField returns the field of v at index, allocating the nil embedded struct pointers on the way.
+func bindField(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// bindName returns the key of a field and whether it's tagged as bindable, or false if the field is ignored.
+func bindName(field reflect.StructField) (name string, bindable bool, ok bool) {
+	if field.Anonymous && indirectKind(field.Type) == reflect.Struct {
+		return "", false, false
+	}
+
+	name = field.Name
+	if tags := field.Tag.Get("copier"); tags != "" {
+		flg, tagName, _ := parseTags(tags)
+		if flg&tagIgnore != 0 {
+			return "", false, false
+		}
+		if tagName != "" {
+			name = tagName
+		}
+		bindable = flg&tagBind != 0
+	}
+	if form, ok := field.Tag.Lookup("form"); ok {
+		if form = strings.Split(form, ",")[0]; form == "-" {
+			return "", false, false
+		} else if form != "" {
+			name = form
+		}
+		bindable = true
+	}
+	return name, bindable, true
+}
+
+func indirectKind(t reflect.Type) reflect.Kind {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind()
+}
+
+// lookupValues returns the values of name, matching keys case-insensitively unless caseSensitive is set.
+func lookupValues(values map[string][]string, name string, caseSensitive bool) []string {
+	if v, ok := values[name]; ok || caseSensitive {
+		return v
+	}
+	for key, v := range values {
+		if strings.EqualFold(key, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// lookupFiles returns the files of name, matching keys case-insensitively unless caseSensitive is set.
+func lookupFiles(files map[string][]*multipart.FileHeader, name string, caseSensitive bool) []*multipart.FileHeader {
+	if v, ok := files[name]; ok || caseSensitive {
+		return v
+	}
+	for key, v := range files {
+		if strings.EqualFold(key, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// bindFiles sets a FileHeader field, or a pointer to it, to the first file and a slice of them to all the files.
+func bindFiles(to reflect.Value, files []*multipart.FileHeader) {
+	switch to.Kind() {
+	case reflect.Slice:
+		elems := reflect.MakeSlice(to.Type(), len(files), len(files))
+		for i := range files {
+			bindFiles(elems.Index(i), files[i:i+1])
+		}
+		to.Set(elems)
+	case reflect.Ptr:
+		if to.Type().Elem() == fileHeaderType {
+			to.Set(reflect.ValueOf(files[0]))
+			return
+		}
+		to.Set(reflect.New(to.Type().Elem()))
+		bindFiles(to.Elem(), files)
+	default:
+		to.Set(reflect.ValueOf(*files[0]))
+	}
+}
@@ -0,0 +1,177 @@
+package copier_test
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/copier"
+)
+
+type SignupCommand struct {
+	Email    string        `form:"email"`
+	Age      int           `form:"age"`
+	Tags     []string      `form:"tag"`
+	Scores   []int         `form:"score"`
+	Remember bool          `copier:"bind"`
+	Timeout  time.Duration `form:"timeout"`
+	Secret   string        `form:"-"`
+	IsAdmin  bool
+}
+
+func TestBindURLValues(t *testing.T) {
+	form := url.Values{
+		"email":    {"jinzhu@example.com", "ignored@example.com"},
+		"age":      {"18"},
+		"tag":      {"a", "b"},
+		"score":    {"1", "2", "3"},
+		"remember": {"true"},
+		"timeout":  {"30s"},
+		"Secret":   {"secret"},
+		"IsAdmin":  {"true"},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := req.ParseForm(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var cmd SignupCommand
+	if err := copier.Bind(&cmd, req.PostForm, copier.Option{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := SignupCommand{
+		Email:    "jinzhu@example.com",
+		Age:      18,
+		Tags:     []string{"a", "b"},
+		Scores:   []int{1, 2, 3},
+		Remember: true,
+		Timeout:  30 * time.Second,
+	}
+	if !reflect.DeepEqual(cmd, expected) {
+		t.Errorf("expected %+v, got %+v", expected, cmd)
+	}
+}
+
+type BindBase struct {
+	ID      int    `form:"id"`
+	Version int    `form:"version"`
+	Trace   string `form:"trace"`
+}
+
+type BindAudit struct {
+	By string `form:"by"`
+}
+
+func TestBindEmbedded(t *testing.T) {
+	type Command struct {
+		Name string `form:"name"`
+		BindBase
+		*BindAudit
+		Trace string `form:"trace_id"`
+	}
+
+	form := url.Values{"id": {"5"}, "version": {"2"}, "name": {"bob"}, "by": {"admin"}, "trace": {"ignored"}, "trace_id": {"t1"}}
+
+	var cmd Command
+	if err := copier.Bind(&cmd, form, copier.Option{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := Command{Name: "bob", BindBase: BindBase{ID: 5, Version: 2}, BindAudit: &BindAudit{By: "admin"}, Trace: "t1"}
+	if !reflect.DeepEqual(cmd, expected) {
+		t.Errorf("expected %+v, got %+v", expected, cmd)
+	}
+
+	// nil embedded pointers are only allocated when one of their fields is bound
+	cmd = Command{}
+	if err := copier.Bind(&cmd, url.Values{"id": {"5"}}, copier.Option{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cmd.ID != 5 || cmd.BindAudit != nil {
+		t.Errorf("unexpected result: %+v", cmd)
+	}
+}
+
+func TestBindHeader(t *testing.T) {
+	type RequestMeta struct {
+		RequestID string   `form:"X-Request-ID"`
+		Accept    []string `copier:"bind"`
+		UserAgent string
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "42")
+	req.Header.Add("Accept", "text/html")
+	req.Header.Add("Accept", "application/json")
+	req.Header.Set("User-Agent", "test")
+
+	var meta RequestMeta
+	if err := copier.Bind(&meta, req.Header, copier.Option{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := RequestMeta{RequestID: "42", Accept: []string{"text/html", "application/json"}}
+	if !reflect.DeepEqual(meta, expected) {
+		t.Errorf("expected %+v, got %+v", expected, meta)
+	}
+}
+
+func TestBindMultipartForm(t *testing.T) {
+	type UploadCommand struct {
+		Title       string                  `form:"title"`
+		Avatar      *multipart.FileHeader   `form:"avatar"`
+		Attachments []*multipart.FileHeader `form:"attachment"`
+		Document    multipart.FileHeader    `form:"avatar"`
+		Copies      []multipart.FileHeader  `form:"attachment"`
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("title", "hello")
+	for _, name := range []string{"avatar", "attachment", "attachment"} {
+		part, _ := writer.CreateFormFile(name, name+".txt")
+		part.Write([]byte(name))
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var cmd UploadCommand
+	if err := copier.Bind(&cmd, req.MultipartForm, copier.Option{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cmd.Title != "hello" || cmd.Avatar == nil || cmd.Avatar.Filename != "avatar.txt" || len(cmd.Attachments) != 2 {
+		t.Errorf("unexpected result: %+v", cmd)
+	}
+	if cmd.Document.Filename != "avatar.txt" || len(cmd.Copies) != 2 || cmd.Copies[1].Filename != "attachment.txt" {
+		t.Errorf("expected files to be bound into non-pointer fields, got %+v", cmd)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	var cmd SignupCommand
+	err := copier.Bind(&cmd, url.Values{"age": {"old"}}, copier.Option{})
+
+	var convErr *copier.ConversionError
+	if !errors.As(err, &convErr) || convErr.Field != "Age" {
+		t.Errorf("expected ConversionError for Age, got %v", err)
+	}
+
+	if err := copier.Bind(&cmd, map[string]string{"age": "18"}, copier.Option{}); !errors.Is(err, copier.ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+}
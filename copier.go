@@ -3,17 +3,23 @@ package copier
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 )
 
+var durationType = reflect.TypeOf(time.Duration(0))
+
 // These flags define options for tag handling
 const (
 	// Denotes that a destination field must be copied to. If copying fails then a panic will ensue.
-	tagMust uint8 = 1 << iota
+	tagMust uint16 = 1 << iota
 
 	// Denotes that the program should not panic when the must flag is on and
 	// value is not copied. The program will return an error instead.
@@ -22,9 +28,33 @@ const (
 	// Ignore a destination field from being copied to.
 	tagIgnore
 
-	// Denotes the fact that the field should be overridden, no matter if the IgnoreEmpty is set
+	// Denotes the fact that the field should be overridden, no matter if the IgnoreEmpty is set or the Overwrite policy
 	tagOverride
 
+	// Denotes that the field shouldn't be copied from a nil pointer, slice, map or interface
+	tagOmitNil
+
+	// Denotes the field identifying slice elements merged with SliceMergeKey
+	tagKey
+
+	// Denotes that a non-zero destination field should be kept, no matter the Overwrite policy
+	tagKeep
+
+	// Denotes that the field may be set from untrusted input
+	tagBind
+
+	// Denotes that the field has a default value, set with `default=value`
+	tagDefault
+
+	// Denotes that the field is transformed, with `transform=name|name`
+	tagTransform
+
+	// Denotes that the source field is redacted in Redact mode
+	tagSensitive
+
+	// Denotes that the field is copied from the first non-zero of several source fields, with `from=Name|Name`
+	tagFrom
+
 	// Denotes that the value as been copied
 	hasCopied
 
@@ -36,17 +66,207 @@ const (
 	Float64 float64 = 0
 )
 
+// OverwritePolicy defines when a destination field holding a value is overwritten
+type OverwritePolicy uint8
+
+const (
+	// OverwriteAlways overwrites destination fields, the default
+	OverwriteAlways OverwritePolicy = iota
+	// OverwriteZero only overwrites destination fields holding their zero value, e.g. to apply defaults
+	OverwriteZero
+	// OverwriteNilPointers overwrites destination fields except non-nil pointers
+	OverwriteNilPointers
+)
+
+// SliceStrategy defines how a source slice is copied into a destination slice
+type SliceStrategy uint8
+
+const (
+	// SliceOverwrite copies elements into the destination by index and truncates it to the source length, the default
+	SliceOverwrite SliceStrategy = iota
+	// SliceReplace clears the destination before copying the elements
+	SliceReplace
+	// SliceAppend appends the elements to the destination
+	SliceAppend
+	// SliceMergeIndex copies elements into the destination by index without truncating it
+	SliceMergeIndex
+	// SliceMergeKey matches elements by the field tagged with `copier:"key"` or set in Option.SliceKeys,
+	// copies into matching elements and appends the others. Duplicate keys in the source return ErrDuplicateKey
+	SliceMergeKey
+)
+
+// ArrayLengthPolicy defines how a slice or an array is copied into an array of a different length
+type ArrayLengthPolicy uint8
+
+const (
+	// ArrayFit truncates longer sources and pads shorter sources with zero values, the default
+	ArrayFit ArrayLengthPolicy = iota
+	// ArrayTruncate truncates longer sources, shorter sources return ErrArrayLength
+	ArrayTruncate
+	// ArrayPad pads shorter sources with zero values, longer sources return ErrArrayLength
+	ArrayPad
+	// ArrayExact returns ErrArrayLength when lengths don't match
+	ArrayExact
+)
+
+// DefaultPolicy defines when the default value of a field tagged with `copier:"default=value"` is set, default values
+// are parsed into the field type like with WeaklyTyped and TextMarshaling, slices from values separated by `|`, e.g. `default=a|b`.
+// Default values never overwrite non-zero fields.
+type DefaultPolicy uint8
+
+const (
+	// DefaultIfMissing sets default values when no source field or method is copied, e.g. when there's none or it's ignored as empty, the default
+	DefaultIfMissing DefaultPolicy = iota
+	// DefaultIfNil also sets default values instead of copying nil pointers, slices, maps and interfaces
+	DefaultIfNil
+	// DefaultIfZero also sets default values instead of copying zero values
+	DefaultIfZero
+)
+
+// RedactMode defines how source fields tagged with `copier:"sensitive"` are copied
+type RedactMode uint8
+
+const (
+	// RedactNone copies sensitive fields like other fields, the default
+	RedactNone RedactMode = iota
+	// RedactMask copies non-empty sensitive strings as `***` and other sensitive values as their zero value
+	RedactMask
+	// RedactZero copies sensitive values as their zero value
+	RedactZero
+)
+
 // Option sets copy options
 type Option struct {
 	// setting this value to true will ignore copying zero values of all the fields, including bools, as well as a
 	// struct having all it's fields set to their zero values respectively (see IsZero() in reflect/value.go)
-	IgnoreEmpty   bool
+	IgnoreEmpty bool
+	// setting this value to true will ignore copying nil pointers, slices, maps and interfaces only,
+	// so explicit zero values like false, 0 or "" are still copied
+	IgnoreNil     bool
 	CaseSensitive bool
 	DeepCopy      bool
 	Converters    []TypeConverter
 	// Custom field name mappings to copy values with different names in `fromValue` and `toValue` types.
 	// Examples can be found in `copier_field_name_mapping_test.go`.
 	FieldNameMapping []FieldNameMapping
+	// setting this value to true will parse and format strings to and from numbers and bools with strconv,
+	// e.g. "42" -> 42 and 42 -> "42", instead of the rune conversion done by reflect (42 -> "*")
+	WeaklyTyped bool
+	// setting this value to true will use MarshalText when copying an encoding.TextMarshaler into a string or []byte,
+	// and UnmarshalText when copying a string or []byte into an encoding.TextUnmarshaler
+	TextMarshaling bool
+	// setting this value to true will ignore errors returned by sql.Scanner and driver.Valuer, leaving the
+	// destination untouched instead of returning a ConversionError
+	IgnoreSQLErrors bool
+	// Wrappers of optional values such as sql.Null[T], copied to and from the value they wrap or a pointer to it.
+	// Examples can be found in `copier_wrapper_test.go`.
+	Wrappers []Wrapper
+	// Overwrite defines which destination fields holding a value may be overwritten, fields tagged with
+	// `copier:"keep"` are only overwritten when zero and fields tagged with `copier:"override"` are always overwritten.
+	// Nested structs which are kept are still merged field by field, non-nil pointers kept by OverwriteNilPointers are left untouched.
+	Overwrite OverwritePolicy
+	// setting this value to true will copy nil slices and maps as empty ones instead of nil
+	NilAsEmpty bool
+	// SliceStrategy defines how slices are copied into existing slices
+	SliceStrategy SliceStrategy
+	// Key fields of slice element types for SliceMergeKey and slice to map copying, used instead of `copier:"key"` tags
+	SliceKeys []SliceKey
+	// ArrayLength defines how slices and arrays are copied into arrays of a different length
+	ArrayLength ArrayLengthPolicy
+	// MapKeyLess sorts map keys when copying a map into a slice, keys are sorted by their value by default
+	MapKeyLess func(a, b interface{}) bool
+	// KeySeparator joins the keys of Flatten and Unflatten, "." by default
+	KeySeparator string
+	// KeyName names the keys of Flatten and Unflatten after the field names, SnakeCase by default
+	KeyName func(fieldName string) string
+	// setting this value to true will merge map values into existing destination values, e.g. structs field by field
+	// and nested maps key by key, instead of replacing them
+	MergeMaps bool
+	// setting this value to true will only copy destination fields tagged with `copier:"bind"` or listed in Allow,
+	// copying other fields returns ErrFieldNotAllowed. Use it when copying from untrusted input.
+	Secure bool
+	// Allow lists destination field names which may be copied in secure mode, a non-empty list enables secure mode
+	Allow []string
+	// setting this value to true will delete destination map keys missing in the source,
+	// as well as destination elements missing in the source when merging slices by key
+	DeleteMissing bool
+	// Defaults defines when the default value of a field tagged with `copier:"default=value"` is applied
+	Defaults DefaultPolicy
+	// setting this value to true will leave the destination untouched when the copy fails or panics, by restoring it
+	// in place, so its nested pointers, maps and slices are kept and their other holders see the restored values.
+	Atomic bool
+
+	// Redact defines how source fields tagged with `copier:"sensitive"` are copied, at any depth
+	Redact RedactMode
+	// Precedence defines which source supplies the destination fields copied from several sources with CopyMany
+	Precedence Precedence
+	// Profiles declare how source types are copied into destination types on top of the tags, see LoadProfiles
+	Profiles []Profile
+
+	// result records the copied fields for CopyWithResult, at path
+	result *Result
+	path   string
+	// redacted denotes that the source has already been redacted
+	redacted bool
+	// nilMaps copies nil maps as nil instead of empty maps, so Diff doesn't report them as changed
+	nilMaps bool
+}
+
+// SliceKey sets the field identifying elements of type Type in slices merged with SliceMergeKey
+type SliceKey struct {
+	Type  interface{}
+	Field string
+}
+
+// allowed reports whether the destination field name may be copied, as secure mode is off,
+// the field is tagged with `copier:"bind"` or listed in opt.Allow.
+func (opt Option) allowed(name string, bitFlags uint16) bool {
+	if !opt.secure() {
+		return true
+	}
+	return bitFlags&tagBind != 0 || opt.allowListed(name)
+}
+
+func (opt Option) secure() bool {
+	return opt.Secure || len(opt.Allow) > 0
+}
+
+func (opt Option) allowListed(name string) bool {
+	for _, allowed := range opt.Allow {
+		if allowed == name || (!opt.CaseSensitive && strings.EqualFold(allowed, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyedSlice reports whether elements of type elem have a key, from their type or the type of the map values they're copied into.
+func (opt Option) keyedSlice(elem, mapElem reflect.Type) bool {
+	for _, typ := range []reflect.Type{elem, mapElem} {
+		if t, _ := indirectType(typ); t.Kind() == reflect.Struct {
+			if _, ok := opt.sliceKey(t); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (opt Option) sliceKey(typ reflect.Type) (string, bool) {
+	typ, _ = indirectType(typ)
+	for _, key := range opt.SliceKeys {
+		if keyType, _ := indirectType(reflect.TypeOf(key.Type)); keyType == typ {
+			return key.Field, true
+		}
+	}
+	for _, field := range deepFields(typ) {
+		if tags := field.Tag.Get("copier"); tags == "" {
+			continue
+		} else if flg, _, _ := parseTags(tags); flg&tagKey != 0 {
+			return field.Name, true
+		}
+	}
+	return "", false
 }
 
 func (opt Option) converters() map[converterPair]TypeConverter {
@@ -65,6 +285,56 @@ func (opt Option) converters() map[converterPair]TypeConverter {
 	return converters
 }
 
+func (opt Option) wrapper(typ reflect.Type) (Wrapper, reflect.Type, bool) {
+	for _, w := range opt.Wrappers {
+		if elem, ok := w.Match(typ); ok {
+			return w, elem, true
+		}
+	}
+	return Wrapper{}, nil, false
+}
+
+// Wrapper unwraps and wraps optional value types, e.g. sql.Null[T] or a user-defined Optional[T]
+type Wrapper struct {
+	// Match reports whether typ is a wrapper type, and returns the type of the value it wraps
+	Match func(typ reflect.Type) (elem reflect.Type, ok bool)
+	// Unwrap returns the value held by wrapper and whether it is valid
+	Unwrap func(wrapper reflect.Value) (value reflect.Value, valid bool)
+	// Wrap sets wrapper to a valid wrapper holding value
+	Wrap func(wrapper reflect.Value, value reflect.Value)
+}
+
+// NullWrapper handles sql.Null[T] and other wrappers with `V` and `Valid` fields
+var NullWrapper = FieldWrapper("V", "Valid")
+
+// FieldWrapper returns a Wrapper for structs holding their value in field valueField
+// and its validity in the bool field validField.
+func FieldWrapper(valueField, validField string) Wrapper {
+	return Wrapper{
+		Match: func(typ reflect.Type) (reflect.Type, bool) {
+			if typ.Kind() != reflect.Struct {
+				return nil, false
+			}
+			value, ok := typ.FieldByName(valueField)
+			if !ok || value.PkgPath != "" {
+				return nil, false
+			}
+			valid, ok := typ.FieldByName(validField)
+			if !ok || valid.PkgPath != "" || valid.Type.Kind() != reflect.Bool {
+				return nil, false
+			}
+			return value.Type, true
+		},
+		Unwrap: func(wrapper reflect.Value) (reflect.Value, bool) {
+			return wrapper.FieldByName(valueField), wrapper.FieldByName(validField).Bool()
+		},
+		Wrap: func(wrapper reflect.Value, value reflect.Value) {
+			wrapper.FieldByName(valueField).Set(value)
+			wrapper.FieldByName(validField).SetBool(true)
+		},
+	}
+}
+
 type TypeConverter struct {
 	SrcType interface{}
 	DstType interface{}
@@ -99,9 +369,16 @@ type FieldNameMapping struct {
 
 // Tag Flags
 type flags struct {
-	BitFlags  map[string]uint8
+	BitFlags  map[string]uint16
 	SrcNames  tagNameMapping
 	DestNames tagNameMapping
+	// Transforms lists the names of the transforms of destination fields
+	Transforms map[string][]string
+	// Sources lists the candidate source fields or methods of destination fields tagged with `from=`
+	Sources map[string][]string
+	// Defaults and Converters hold the default values and converters of destination fields set by a profile
+	Defaults   map[string]string
+	Converters map[string]TypeConverter
 }
 
 // Field Tag name mapping
@@ -121,6 +398,16 @@ func CopyWithOption(toValue interface{}, fromValue interface{}, opt Option) (err
 }
 
 func copier(toValue interface{}, fromValue interface{}, opt Option) (err error) {
+	if opt.Atomic {
+		return copyAtomic(toValue, fromValue, opt)
+	}
+
+	// copy from a redacted copy of the source, cloning only what holds sensitive fields
+	if opt.Redact != RedactNone && !opt.redacted && fromValue != nil {
+		opt.redacted = true
+		fromValue = redactValue(reflect.ValueOf(fromValue), opt.Redact, map[uintptr]reflect.Value{}).Interface()
+	}
+
 	var (
 		isSlice    bool
 		amount     = 1
@@ -155,6 +442,39 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 		}()
 	}
 
+	// Unwrap or wrap optional values
+	if len(opt.Wrappers) > 0 {
+		if ok, err := copyWrapper(to, from, opt, converters); err != nil || ok {
+			return err
+		}
+	}
+
+	// Marshal or unmarshal text for types implementing encoding.TextMarshaler and encoding.TextUnmarshaler
+	if opt.TextMarshaling {
+		if ok, err := copyText(to, from); err != nil || ok {
+			return err
+		}
+	}
+
+	// Parse or format strings to and from numbers and bools
+	if opt.WeaklyTyped {
+		if ok, err := coerce(to, from); err != nil || ok {
+			return err
+		}
+	}
+
+	// Copy arrays element by element, to and from slices as well
+	if isArrayCopy(to, from) {
+		if to.Kind() == reflect.Slice {
+			return copier(to.Addr().Interface(), arrayToSlice(from).Interface(), opt)
+		}
+		if from.Type().AssignableTo(to.Type()) && !opt.DeepCopy {
+			to.Set(from)
+			return
+		}
+		return copyArray(to, from, opt, converters)
+	}
+
 	// Just set it if possible to assign for normal types
 	if from.Kind() != reflect.Slice && from.Kind() != reflect.Struct && from.Kind() != reflect.Map && (from.Type().AssignableTo(to.Type()) || from.Type().ConvertibleTo(to.Type())) {
 		if !isPtrFrom || !opt.DeepCopy {
@@ -167,23 +487,57 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 		return
 	}
 
-	if from.Kind() != reflect.Slice && fromType.Kind() == reflect.Map && toType.Kind() == reflect.Map {
-		if !fromType.Key().ConvertibleTo(toType.Key()) {
-			return ErrMapKeyNotMatch
-		}
+	// slices of structs with a key field are copied into maps, other slices are skipped
+	if from.Kind() == reflect.Slice && to.Kind() == reflect.Map && opt.keyedSlice(from.Type().Elem(), to.Type().Elem()) {
+		return sliceToMap(to, from, opt, converters)
+	}
 
-		if to.IsNil() {
+	// maps are copied into slices of structs with a key field as well, other maps are skipped
+	if from.Kind() == reflect.Map && to.Kind() == reflect.Slice && opt.keyedSlice(to.Type().Elem(), from.Type().Elem()) {
+		return mapToSlice(to, from, opt)
+	}
+
+	if from.Kind() != reflect.Slice && fromType.Kind() == reflect.Map && toType.Kind() == reflect.Map {
+		if to.IsNil() && (!from.IsNil() || !opt.nilMaps || opt.NilAsEmpty) {
 			to.Set(reflect.MakeMapWithSize(toType, from.Len()))
 		}
 
+		// keys are converted like values, through converters as well, and mustn't collide
+		copiedKeys := map[interface{}]reflect.Value{}
+		var denied []string
 		for _, k := range from.MapKeys() {
 			toKey := indirect(reflect.New(toType.Key()))
-			isSet, err := set(toKey, k, opt.DeepCopy, converters)
+			isSet, err := set(toKey, k, opt, converters)
 			if err != nil {
 				return err
 			}
 			if !isSet {
-				return fmt.Errorf("%w map, old key: %v, new key: %v", ErrNotSupported, k.Type(), toType.Key())
+				if toKey.Kind() != reflect.Struct || indirect(k).Kind() != reflect.Struct {
+					return fmt.Errorf("%w, old key: %v, new key: %v", ErrMapKeyNotMatch, k.Type(), toType.Key())
+				}
+				keyOpt := opt
+				keyOpt.result = nil
+				if err = copier(toKey.Addr().Interface(), k.Interface(), keyOpt); err != nil {
+					return err
+				}
+			}
+			if prev, ok := copiedKeys[toKey.Interface()]; ok {
+				return fmt.Errorf("%w: %v and %v both convert to %v", ErrDuplicateKey, prev, k, toKey)
+			}
+			copiedKeys[toKey.Interface()] = k
+
+			if existing := to.MapIndex(toKey); opt.MergeMaps && existing.IsValid() {
+				merged, err := mergeMapValue(existing, from.MapIndex(k), opt, converters)
+				if err != nil {
+					return err
+				}
+				if merged.IsValid() {
+					to.SetMapIndex(toKey, merged)
+					if opt.result != nil {
+						opt.written(keyString(toKey))
+					}
+					continue
+				}
 			}
 
 			elemType := toType.Elem()
@@ -191,50 +545,75 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 				elemType, _ = indirectType(elemType)
 			}
 			toValue := indirect(reflect.New(elemType))
-			isSet, err = set(toValue, from.MapIndex(k), opt.DeepCopy, converters)
+			isSet, err = set(toValue, from.MapIndex(k), opt, converters)
 			if err != nil {
 				return err
 			}
 			if !isSet {
 				if err = copier(toValue.Addr().Interface(), from.MapIndex(k).Interface(), opt); err != nil {
-					return err
+					// values with fields denied in secure mode are still set with their allowed fields
+					if denied, err = nestedDenied(denied, keyString(toKey), err); err != nil {
+						return err
+					}
 				}
 			}
 
 			for {
 				if elemType == toType.Elem() {
 					to.SetMapIndex(toKey, toValue)
+					if opt.result != nil {
+						opt.written(keyString(toKey))
+					}
 					break
 				}
 				elemType = reflect.PointerTo(elemType)
 				toValue = toValue.Addr()
 			}
 		}
+
+		if opt.DeleteMissing {
+			for _, k := range to.MapKeys() {
+				if _, ok := copiedKeys[k.Interface()]; !ok {
+					to.SetMapIndex(k, reflect.Value{})
+				}
+			}
+		}
+		if len(denied) > 0 {
+			return &DeniedError{Fields: denied}
+		}
 		return
 	}
 
 	if from.Kind() == reflect.Slice && to.Kind() == reflect.Slice {
+		if opt.SliceStrategy != SliceOverwrite {
+			return mergeSlice(to, from, opt, converters)
+		}
 		// Return directly if both slices are nil
 		if from.IsNil() && to.IsNil() {
+			if opt.NilAsEmpty {
+				to.Set(reflect.MakeSlice(to.Type(), 0, 0))
+			}
 			return
 		}
 		if to.IsNil() {
 			slice := reflect.MakeSlice(reflect.SliceOf(to.Type().Elem()), from.Len(), from.Cap())
 			to.Set(slice)
 		}
-		if fromType.ConvertibleTo(toType) {
+		if fromType.ConvertibleTo(toType) || (opt.WeaklyTyped && coercible(fromType, toType)) {
+			var denied []string
 			for i := 0; i < from.Len(); i++ {
 				if to.Len() < i+1 {
 					to.Set(reflect.Append(to, reflect.New(to.Type().Elem()).Elem()))
 				}
-				isSet, err := set(to.Index(i), from.Index(i), opt.DeepCopy, converters)
+				isSet, err := set(to.Index(i), from.Index(i), opt, converters)
 				if err != nil {
 					return err
 				}
 				if !isSet {
-					// ignore error while copy slice element
-					err = copier(to.Index(i).Addr().Interface(), from.Index(i).Interface(), opt)
+					// ignore error while copy slice element, except fields denied in secure mode which are collected
+					err = copier(to.Index(i).Addr().Interface(), from.Index(i).Interface(), opt.at(strconv.Itoa(i)))
 					if err != nil {
+						denied, _ = nestedDenied(denied, strconv.Itoa(i), err)
 						continue
 					}
 				}
@@ -244,17 +623,21 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 				to.SetLen(from.Len())
 			}
 
+			if len(denied) > 0 {
+				return &DeniedError{Fields: denied}
+			}
 			return
 		}
 	}
 
 	if fromType.Kind() != reflect.Struct || toType.Kind() != reflect.Struct {
 		// skip not supported type
+		opt.skipped("", SkipUnsupported)
 		return
 	}
 
 	if len(converters) > 0 {
-		if ok, e := set(to, from, opt.DeepCopy, converters); e == nil && ok {
+		if ok, e := set(to, from, opt, converters); e == nil && ok {
 			// converter supported
 			return
 		}
@@ -267,9 +650,20 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 		}
 	}
 
+	// destination fields which weren't copied as they aren't allowed in secure mode
+	var denied []string
+	profile := opt.profiles()[converterPair{SrcType: fromType, DstType: toType}]
+	defaults := hasDefaults(toType) || profile.hasDefaults()
+
 	for i := 0; i < amount; i++ {
 		var dest, source reflect.Value
 
+		// denied fields of slice elements are prefixed with their index
+		opt, prefix := opt, ""
+		if isSlice && from.Kind() == reflect.Slice {
+			opt, prefix = opt.at(strconv.Itoa(i)), strconv.Itoa(i)+"."
+		}
+
 		if isSlice {
 			// source
 			if from.Kind() == reflect.Slice {
@@ -285,7 +679,7 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 		}
 
 		if len(converters) > 0 {
-			if ok, e := set(dest, source, opt.DeepCopy, converters); e == nil && ok {
+			if ok, e := set(dest, source, opt, converters); e == nil && ok {
 				if isSlice {
 					// FIXME: maybe should check the other types?
 					if to.Type().Elem().Kind() == reflect.Ptr {
@@ -317,10 +711,15 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 		if err != nil {
 			return err
 		}
+		if err := profile.apply(flgs); err != nil {
+			return err
+		}
 
 		// check source
 		if source.IsValid() {
-			copyUnexportedStructFields(dest, source)
+			if opt.Overwrite != OverwriteZero || dest.IsZero() {
+				copyUnexportedStructFields(dest, source)
+			}
 
 			// Copy from source field to dest field or method
 			fromTypeFields := deepFields(fromType)
@@ -332,6 +731,7 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 
 				// Check if we should ignore copying
 				if (fieldFlags & tagIgnore) != 0 {
+					opt.skipped(name, SkipIgnored)
 					continue
 				}
 
@@ -339,7 +739,22 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 
 				srcFieldName, destFieldName := getFieldName(name, flgs, fieldNamesMapping)
 
-				if fromField := fieldByNameOrZeroValue(source, srcFieldName); fromField.IsValid() && !shouldIgnore(fromField, fieldFlags, opt.IgnoreEmpty) {
+				// fields tagged with `from=` are copied from their candidate sources below
+				if _, ok := flgs.Sources[destFieldName]; ok {
+					continue
+				}
+				if flgs.BitFlags[destFieldName]&tagIgnore != 0 {
+					opt.skipped(destFieldName, SkipIgnored)
+					continue
+				}
+
+				if fromField := fieldByNameOrZeroValue(source, srcFieldName); fromField.IsValid() && !shouldIgnore(fromField, fieldFlags|flgs.BitFlags[destFieldName], opt) {
+					if transforms := flgs.Transforms[destFieldName]; len(transforms) > 0 {
+						if fromField, err = applyTransforms(fromField, transforms); err != nil {
+							return transformError(err, destFieldName)
+						}
+					}
+
 					// process for nested anonymous field
 					destFieldNotSet := false
 					if f, ok := dest.Type().FieldByName(destFieldName); ok {
@@ -371,20 +786,38 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 
 					toField := fieldByName(dest, destFieldName, opt.CaseSensitive)
 					if toField.IsValid() {
-						if toField.CanSet() {
-							isSet, err := set(toField, fromField, opt.DeepCopy, converters)
-							if err != nil {
-								return err
+						if !toField.CanSet() {
+							opt.skipped(destFieldName, SkipUnsupported)
+						} else if !opt.allowed(destFieldName, flgs.BitFlags[destFieldName]) {
+							denied = appendOnce(denied, prefix+destFieldName)
+							opt.skipped(destFieldName, SkipNotAllowed)
+						} else {
+							var isSet bool
+							if !shouldOverwrite(toField, flgs.BitFlags[destFieldName], opt.Overwrite) {
+								// keep the existing value, nested structs are merged
+								if isSet = !shouldMerge(toField, fromField, flgs.BitFlags[destFieldName], opt.Overwrite); isSet {
+									opt.skipped(destFieldName, SkipKept)
+								}
+							} else if isSet, err = set(toField, fromField, opt, flgs.fieldConverters(destFieldName, converters)); err != nil {
+								return withField(err, destFieldName)
+							} else if isSet {
+								opt.written(destFieldName)
 							}
 							if !isSet {
-								if err := copier(toField.Addr().Interface(), fromField.Interface(), opt); err != nil {
-									return err
+								if err := copier(toField.Addr().Interface(), fromField.Interface(), opt.at(destFieldName)); err != nil {
+									// fields denied in nested structs are collected like top-level ones
+									if denied, err = nestedDenied(denied, prefix+destFieldName, err); err != nil {
+										return withField(err, destFieldName)
+									}
 								}
 							}
 							if fieldFlags != 0 {
 								// Note that a copy was made
 								flgs.BitFlags[name] = fieldFlags | hasCopied
 							}
+							if defaults {
+								flgs.BitFlags[destFieldName] |= hasCopied
+							}
 						}
 					} else {
 						// try to set to method
@@ -396,15 +829,81 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 						}
 
 						if toMethod.IsValid() && toMethod.Type().NumIn() == 1 && fromField.Type().AssignableTo(toMethod.Type().In(0)) {
-							toMethod.Call([]reflect.Value{fromField})
+							if !opt.allowed(destFieldName, 0) {
+								denied = appendOnce(denied, prefix+destFieldName)
+								opt.skipped(destFieldName, SkipNotAllowed)
+							} else {
+								toMethod.Call([]reflect.Value{fromField})
+								opt.written(destFieldName)
+							}
+						}
+					}
+				} else if fromField.IsValid() {
+					opt.skipped(name, SkipEmpty)
+				}
+			}
+
+			// Copy the first non-zero candidate source field or method to dest fields tagged with `from=`
+			for _, field := range deepFields(toType) {
+				name := field.Name
+				names, ok := flgs.Sources[name]
+				if !ok {
+					continue
+				}
+
+				fromField, ok := coalesce(source, names, opt)
+				if !ok {
+					continue
+				}
+				if shouldIgnore(fromField, flgs.BitFlags[name], opt) {
+					opt.skipped(name, SkipEmpty)
+					continue
+				}
+				if transforms := flgs.Transforms[name]; len(transforms) > 0 {
+					if fromField, err = applyTransforms(fromField, transforms); err != nil {
+						return transformError(err, name)
+					}
+				}
+
+				toField := fieldByName(dest, name, opt.CaseSensitive)
+				if !toField.IsValid() || !toField.CanSet() {
+					opt.skipped(name, SkipUnsupported)
+					continue
+				}
+				if !opt.allowed(name, flgs.BitFlags[name]) {
+					denied = appendOnce(denied, prefix+name)
+					opt.skipped(name, SkipNotAllowed)
+					continue
+				}
+
+				var isSet bool
+				if !shouldOverwrite(toField, flgs.BitFlags[name], opt.Overwrite) {
+					// keep the existing value, nested structs are merged
+					if isSet = !shouldMerge(toField, fromField, flgs.BitFlags[name], opt.Overwrite); isSet {
+						opt.skipped(name, SkipKept)
+					}
+				} else if isSet, err = set(toField, fromField, opt, flgs.fieldConverters(name, converters)); err != nil {
+					return withField(err, name)
+				} else if isSet {
+					opt.written(name)
+				}
+				if !isSet {
+					if err := copier(toField.Addr().Interface(), fromField.Interface(), opt.at(name)); err != nil {
+						if denied, err = nestedDenied(denied, prefix+name, err); err != nil {
+							return withField(err, name)
 						}
 					}
 				}
+				// Note that a copy was made, whichever candidate matched
+				flgs.BitFlags[name] |= hasCopied
 			}
 
 			// Copy from from method to dest field
 			for _, field := range deepFields(toType) {
 				name := field.Name
+				if _, ok := flgs.Sources[name]; ok || flgs.BitFlags[name]&tagIgnore != 0 {
+					continue
+				}
 				srcFieldName, destFieldName := getFieldName(name, flgs, getFieldNamesMapping(mappings, fromType, toType))
 
 				var fromMethod reflect.Value
@@ -414,15 +913,53 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 					fromMethod = source.MethodByName(srcFieldName)
 				}
 
-				if fromMethod.IsValid() && fromMethod.Type().NumIn() == 0 && fromMethod.Type().NumOut() == 1 && !shouldIgnore(fromMethod, flgs.BitFlags[name], opt.IgnoreEmpty) {
+				if fromMethod.IsValid() && fromMethod.Type().NumIn() == 0 && fromMethod.Type().NumOut() == 1 && !shouldIgnore(fromMethod, flgs.BitFlags[name], opt) {
 					if toField := fieldByName(dest, destFieldName, opt.CaseSensitive); toField.IsValid() && toField.CanSet() {
+						if !shouldOverwrite(toField, flgs.BitFlags[name], opt.Overwrite) {
+							opt.skipped(destFieldName, SkipKept)
+							continue
+						}
+						if !opt.allowed(destFieldName, flgs.BitFlags[name]) {
+							denied = appendOnce(denied, prefix+destFieldName)
+							opt.skipped(destFieldName, SkipNotAllowed)
+							continue
+						}
 						values := fromMethod.Call([]reflect.Value{})
+						if opt.Redact != RedactNone && len(values) >= 1 {
+							values[0] = redactValue(values[0], opt.Redact, map[uintptr]reflect.Value{})
+						}
+						if transforms := flgs.Transforms[destFieldName]; len(values) >= 1 && len(transforms) > 0 {
+							if values[0], err = applyTransforms(values[0], transforms); err != nil {
+								return transformError(err, destFieldName)
+							}
+						}
 						if len(values) >= 1 {
-							set(toField, values[0], opt.DeepCopy, converters)
+							if isSet, _ := set(toField, values[0], opt, flgs.fieldConverters(destFieldName, converters)); isSet {
+								opt.written(destFieldName)
+								if defaults {
+									flgs.BitFlags[destFieldName] |= hasCopied
+								}
+							}
 						}
 					}
 				}
 			}
+
+			// destination fields tagged with a default value which weren't copied
+			if defaults {
+				if err := applyDefaults(dest, flgs.BitFlags, flgs.Defaults, opt, converters); err != nil {
+					return err
+				}
+			}
+
+			// destination fields without any matching source field or method
+			if opt.result != nil {
+				for _, field := range deepFields(toType) {
+					if !opt.result.has(opt.fieldPath(field.Name)) {
+						opt.skipped(field.Name, SkipNoMatch)
+					}
+				}
+			}
 		}
 
 		if isSlice && to.Kind() == reflect.Slice {
@@ -430,12 +967,12 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 				if to.Len() < i+1 {
 					to.Set(reflect.Append(to, dest.Addr()))
 				} else {
-					isSet, err := set(to.Index(i), dest.Addr(), opt.DeepCopy, converters)
+					isSet, err := set(to.Index(i), dest.Addr(), opt, converters)
 					if err != nil {
 						return err
 					}
 					if !isSet {
-						// ignore error while copy slice element
+						// ignore error while copy slice element, the fields denied in secure mode were collected copying into dest
 						err = copier(to.Index(i).Addr().Interface(), dest.Addr().Interface(), opt)
 						if err != nil {
 							continue
@@ -446,12 +983,12 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 				if to.Len() < i+1 {
 					to.Set(reflect.Append(to, dest))
 				} else {
-					isSet, err := set(to.Index(i), dest, opt.DeepCopy, converters)
+					isSet, err := set(to.Index(i), dest, opt, converters)
 					if err != nil {
 						return err
 					}
 					if !isSet {
-						// ignore error while copy slice element
+						// ignore error while copy slice element, the fields denied in secure mode were collected copying into dest
 						err = copier(to.Index(i).Addr().Interface(), dest.Interface(), opt)
 						if err != nil {
 							continue
@@ -463,72 +1000,439 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 			to.Set(dest)
 		}
 
-		err = checkBitFlags(flgs.BitFlags)
+		if err := checkBitFlags(flgs.BitFlags); err != nil {
+			return err
+		}
 	}
 
+	if err == nil && len(denied) > 0 {
+		err = &DeniedError{Fields: denied}
+	}
 	return
 }
 
-func getFieldNamesMapping(mappings map[converterPair]FieldNameMapping, fromType reflect.Type, toType reflect.Type) map[string]string {
-	var fieldNamesMapping map[string]string
+// applyDefaults sets the zero fields of the struct v tagged with `copier:"default=value"` or with a default value in values
+// which weren't copied, walking into the nested structs which weren't copied either.
+func applyDefaults(v reflect.Value, bitFlags map[string]uint16, values map[string]string, opt Option, converters map[converterPair]TypeConverter) error {
+	for i := 0; i < v.NumField(); i++ {
+		field, toField := v.Type().Field(i), v.Field(i)
+		if field.PkgPath != "" || bitFlags[field.Name]&hasCopied != 0 {
+			continue
+		}
 
-	if len(mappings) > 0 {
-		pair := converterPair{
-			SrcType: fromType,
-			DstType: toType,
+		value, ok := values[field.Name]
+		if !ok {
+			value, ok = tagOption(field.Tag.Get("copier"), "default")
 		}
-		if v, ok := mappings[pair]; ok {
-			fieldNamesMapping = v.Mapping
+		if ok {
+			if toField.IsZero() {
+				if err := setDefault(toField, value, opt, converters); err != nil {
+					return withField(err, field.Name)
+				}
+				opt.written(field.Name)
+			}
+			continue
 		}
-	}
-	return fieldNamesMapping
-}
 
-func fieldByNameOrZeroValue(source reflect.Value, fieldName string) (value reflect.Value) {
-	defer func() {
-		if err := recover(); err != nil {
-			value = reflect.Value{}
+		if toField.Kind() == reflect.Ptr && !toField.IsNil() {
+			toField = toField.Elem()
 		}
-	}()
+		if toField.Kind() != reflect.Struct || !hasDefaults(toField.Type()) {
+			continue
+		}
+		if field.Anonymous {
+			// promoted fields share the flags of their parent
+			if err := applyDefaults(toField, bitFlags, values, opt, converters); err != nil {
+				return err
+			}
+		} else if err := applyDefaults(toField, nil, nil, opt.at(field.Name), converters); err != nil {
+			return withField(err, field.Name)
+		}
+	}
+	return nil
+}
 
-	return source.FieldByName(fieldName)
+// setDefault parses the default value of a field into it.
+func setDefault(to reflect.Value, value string, opt Option, converters map[converterPair]TypeConverter) error {
+	from := reflect.ValueOf(value)
+	if t, _ := indirectType(to.Type()); to.Type().Kind() == reflect.Slice && t.Kind() != reflect.Uint8 {
+		from = reflect.ValueOf(strings.Split(value, "|"))
+	}
+	return copyValue(to, from, Option{WeaklyTyped: true, TextMarshaling: true, Converters: opt.Converters, Wrappers: opt.Wrappers}, converters)
 }
 
-func copyUnexportedStructFields(to, from reflect.Value) {
-	if from.Kind() != reflect.Struct || to.Kind() != reflect.Struct || !from.Type().AssignableTo(to.Type()) {
-		return
+var defaultsMap sync.Map
+
+// hasDefaults reports whether the struct type t or its nested structs have fields tagged with `copier:"default=value"`.
+func hasDefaults(t reflect.Type) bool {
+	if cached, ok := defaultsMap.Load(t); ok {
+		return cached.(bool)
 	}
+	found := findDefaults(t, map[reflect.Type]bool{})
+	defaultsMap.Store(t, found)
+	return found
+}
 
-	// create a shallow copy of 'to' to get all fields
-	tmp := indirect(reflect.New(to.Type()))
-	tmp.Set(from)
+func findDefaults(t reflect.Type, visited map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visited[t] {
+		return false
+	}
+	visited[t] = true
 
-	// revert exported fields
-	for i := 0; i < to.NumField(); i++ {
-		if tmp.Field(i).CanSet() {
-			tmp.Field(i).Set(to.Field(i))
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if _, ok := tagOption(field.Tag.Get("copier"), "default"); ok || findDefaults(field.Type, visited) {
+			return true
 		}
 	}
-	to.Set(tmp)
+	return false
 }
 
-func shouldIgnore(v reflect.Value, bitFlags uint8, ignoreEmpty bool) bool {
-	return ignoreEmpty && bitFlags&tagOverride == 0 && v.IsZero()
+// coalesce returns the first non-zero of the source fields or methods named names, or the first one found if they're all zero.
+// It reports whether any of them was found.
+func coalesce(source reflect.Value, names []string, opt Option) (value reflect.Value, found bool) {
+	for _, name := range names {
+		var v reflect.Value
+		if field, ok := source.Type().FieldByName(name); ok && field.PkgPath == "" {
+			v = fieldByNameOrZeroValue(source, name)
+		} else {
+			var method reflect.Value
+			if source.CanAddr() {
+				method = source.Addr().MethodByName(name)
+			} else {
+				method = source.MethodByName(name)
+			}
+			if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+				continue
+			}
+			v = method.Call([]reflect.Value{})[0]
+			if opt.Redact != RedactNone {
+				v = redactValue(v, opt.Redact, map[uintptr]reflect.Value{})
+			}
+		}
+		if !v.IsValid() {
+			continue
+		}
+
+		if !found {
+			value, found = v, true
+		}
+		if !v.IsZero() {
+			return v, true
+		}
+	}
+	return
 }
 
-var deepFieldsLock sync.RWMutex
-var deepFieldsMap = make(map[reflect.Type][]reflect.StructField)
+// tagOption returns the value of an option of copier tags, e.g. `10` for `default=10`.
+func tagOption(tags, key string) (string, bool) {
+	for _, t := range strings.Split(tags, ",") {
+		if strings.HasPrefix(t, key+"=") {
+			return strings.TrimPrefix(t, key+"="), true
+		}
+	}
+	return "", false
+}
 
-func deepFields(reflectType reflect.Type) []reflect.StructField {
-	deepFieldsLock.RLock()
-	cache, ok := deepFieldsMap[reflectType]
-	deepFieldsLock.RUnlock()
-	if ok {
-		return cache
+// copyAtomic copies into the destination, which is restored in place when the copy fails or panics.
+func copyAtomic(toValue interface{}, fromValue interface{}, opt Option) (err error) {
+	opt.Atomic = false
+	to := indirect(reflect.ValueOf(toValue))
+	if !to.CanAddr() {
+		return ErrInvalidCopyDestination
 	}
-	var res []reflect.StructField
-	if reflectType, _ = indirectType(reflectType); reflectType.Kind() == reflect.Struct {
-		fields := make([]reflect.StructField, 0, reflectType.NumField())
+
+	saved := saveState(to, map[uintptr]bool{})
+	defer func() {
+		if r := recover(); r != nil {
+			saved.restore()
+			panic(r)
+		}
+	}()
+	if err = copier(toValue, fromValue, opt); err != nil {
+		saved.restore()
+	}
+	return err
+}
+
+// state is the saved value of an addressable destination, along with the states of the values it points to,
+// so the destination can be restored in place, keeping its pointers, maps and slices.
+type state struct {
+	target reflect.Value
+	value  reflect.Value
+	// entries of a saved map, restored into value
+	entries [][2]reflect.Value
+	nested  []*state
+}
+
+func saveState(v reflect.Value, visited map[uintptr]bool) *state {
+	s := &state{target: v, value: reflect.New(v.Type()).Elem()}
+	s.value.Set(v)
+	s.nested = nestedStates(v, visited)
+	return s
+}
+
+// nestedStates saves the values v points to, which copying may modify in place.
+func nestedStates(v reflect.Value, visited map[uintptr]bool) []*state {
+	var states []*state
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || visited[v.Pointer()] {
+			return nil
+		}
+		visited[v.Pointer()] = true
+		states = append(states, saveState(v.Elem(), visited))
+	case reflect.Interface:
+		if !v.IsNil() {
+			states = nestedStates(v.Elem(), visited)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				states = append(states, nestedStates(v.Field(i), visited)...)
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			states = append(states, nestedStates(v.Index(i), visited)...)
+		}
+	case reflect.Slice:
+		if v.IsNil() || v.Len() == 0 || visited[v.Pointer()] {
+			return nil
+		}
+		visited[v.Pointer()] = true
+		for i := 0; i < v.Len(); i++ {
+			states = append(states, saveState(v.Index(i), visited))
+		}
+	case reflect.Map:
+		if v.IsNil() || visited[v.Pointer()] {
+			return nil
+		}
+		visited[v.Pointer()] = true
+		s := &state{value: v}
+		for _, key := range v.MapKeys() {
+			value := v.MapIndex(key)
+			s.entries = append(s.entries, [2]reflect.Value{key, value})
+			s.nested = append(s.nested, nestedStates(value, visited)...)
+		}
+		states = append(states, s)
+	}
+	return states
+}
+
+// restore sets the saved values back, in place.
+func (s *state) restore() {
+	if s.target.IsValid() {
+		s.target.Set(s.value)
+	} else {
+		// saved maps have no target, their entries are restored
+		for _, key := range s.value.MapKeys() {
+			s.value.SetMapIndex(key, reflect.Value{})
+		}
+		for _, entry := range s.entries {
+			s.value.SetMapIndex(entry[0], entry[1])
+		}
+	}
+	for _, nested := range s.nested {
+		nested.restore()
+	}
+}
+
+// cloneValue returns a deep clone of v, whatever the tags of its fields are. Unexported fields are copied as is.
+func cloneValue(v reflect.Value, cloned map[uintptr]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		if clone, ok := cloned[v.Pointer()]; ok {
+			return clone
+		}
+		clone := reflect.New(v.Type().Elem())
+		cloned[v.Pointer()] = clone
+		clone.Elem().Set(cloneValue(v.Elem(), cloned))
+		return clone
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.New(v.Type()).Elem()
+		clone.Set(cloneValue(v.Elem(), cloned))
+		return clone
+	case reflect.Struct:
+		clone := reflect.New(v.Type()).Elem()
+		clone.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if clone.Field(i).CanSet() {
+				clone.Field(i).Set(cloneValue(v.Field(i), cloned))
+			}
+		}
+		return clone
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, key := range v.MapKeys() {
+			clone.SetMapIndex(key, cloneValue(v.MapIndex(key), cloned))
+		}
+		return clone
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			clone.Index(i).Set(cloneValue(v.Index(i), cloned))
+		}
+		return clone
+	case reflect.Array:
+		clone := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			clone.Index(i).Set(cloneValue(v.Index(i), cloned))
+		}
+		return clone
+	}
+	return v
+}
+
+func appendOnce(names []string, name string) []string {
+	for _, n := range names {
+		if n == name {
+			return names
+		}
+	}
+	return append(names, name)
+}
+
+func getFieldNamesMapping(mappings map[converterPair]FieldNameMapping, fromType reflect.Type, toType reflect.Type) map[string]string {
+	var fieldNamesMapping map[string]string
+
+	if len(mappings) > 0 {
+		pair := converterPair{
+			SrcType: fromType,
+			DstType: toType,
+		}
+		if v, ok := mappings[pair]; ok {
+			fieldNamesMapping = v.Mapping
+		}
+	}
+	return fieldNamesMapping
+}
+
+func fieldByNameOrZeroValue(source reflect.Value, fieldName string) (value reflect.Value) {
+	defer func() {
+		if err := recover(); err != nil {
+			value = reflect.Value{}
+		}
+	}()
+
+	return source.FieldByName(fieldName)
+}
+
+func copyUnexportedStructFields(to, from reflect.Value) {
+	if from.Kind() != reflect.Struct || to.Kind() != reflect.Struct || !from.Type().AssignableTo(to.Type()) {
+		return
+	}
+
+	// create a shallow copy of 'to' to get all fields
+	tmp := indirect(reflect.New(to.Type()))
+	tmp.Set(from)
+
+	// revert exported fields
+	for i := 0; i < to.NumField(); i++ {
+		if tmp.Field(i).CanSet() {
+			tmp.Field(i).Set(to.Field(i))
+		}
+	}
+	to.Set(tmp)
+}
+
+func shouldIgnore(v reflect.Value, bitFlags uint16, opt Option) bool {
+	if bitFlags&tagOverride != 0 {
+		return false
+	}
+	// sources of fields with a default value are ignored according to the Defaults policy
+	if bitFlags&tagDefault != 0 && ((opt.Defaults == DefaultIfZero && v.IsZero()) || (opt.Defaults == DefaultIfNil && isNil(v))) {
+		return true
+	}
+	if opt.IgnoreEmpty && v.IsZero() {
+		return true
+	}
+	return (opt.IgnoreNil || bitFlags&tagOmitNil != 0) && isNil(v)
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
+
+func shouldOverwrite(to reflect.Value, bitFlags uint16, policy OverwritePolicy) bool {
+	switch {
+	case bitFlags&tagOverride != 0:
+		return true
+	case bitFlags&tagKeep != 0 || policy == OverwriteZero:
+		return to.IsZero()
+	case policy == OverwriteNilPointers:
+		return to.Kind() != reflect.Ptr || to.IsNil()
+	}
+	return true
+}
+
+// shouldMerge reports whether a destination field which isn't overwritten is merged field by field with `from`,
+// non-nil pointers kept by OverwriteNilPointers are left untouched.
+func shouldMerge(to, from reflect.Value, bitFlags uint16, policy OverwritePolicy) bool {
+	if policy == OverwriteNilPointers && bitFlags&tagKeep == 0 && to.Kind() == reflect.Ptr {
+		return false
+	}
+	return canMerge(to, from)
+}
+
+// hasFields reports whether t is a struct with exported fields, or a pointer, slice, array or map of such structs.
+func hasFields(t reflect.Type) bool {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		case reflect.Struct:
+			return len(deepFields(t)) > 0
+		default:
+			return false
+		}
+	}
+}
+
+// canMerge reports whether `from` can be copied field by field into the existing struct `to`.
+func canMerge(to, from reflect.Value) bool {
+	if from.Kind() == reflect.Ptr && from.IsNil() {
+		return false
+	}
+	to = indirect(to)
+	return to.IsValid() && to.Kind() == reflect.Struct && len(deepFields(to.Type())) > 0 && indirect(from).Kind() == reflect.Struct
+}
+
+var deepFieldsLock sync.RWMutex
+var deepFieldsMap = make(map[reflect.Type][]reflect.StructField)
+
+func deepFields(reflectType reflect.Type) []reflect.StructField {
+	deepFieldsLock.RLock()
+	cache, ok := deepFieldsMap[reflectType]
+	deepFieldsLock.RUnlock()
+	if ok {
+		return cache
+	}
+	var res []reflect.StructField
+	if reflectType, _ = indirectType(reflectType); reflectType.Kind() == reflect.Struct {
+		fields := make([]reflect.StructField, 0, reflectType.NumField())
 
 		for i := 0; i < reflectType.NumField(); i++ {
 			v := reflectType.Field(i)
@@ -567,7 +1471,7 @@ func indirectType(reflectType reflect.Type) (_ reflect.Type, isPtr bool) {
 	return reflectType, isPtr
 }
 
-func set(to, from reflect.Value, deepCopy bool, converters map[converterPair]TypeConverter) (bool, error) {
+func set(to, from reflect.Value, opt Option, converters map[converterPair]TypeConverter) (bool, error) {
 	if !from.IsValid() {
 		return true, nil
 	}
@@ -577,6 +1481,31 @@ func set(to, from reflect.Value, deepCopy bool, converters map[converterPair]Typ
 		return true, nil
 	}
 
+	if len(opt.Wrappers) > 0 {
+		if ok, err := copyWrapper(to, from, opt, converters); err != nil || ok {
+			return ok, err
+		}
+	}
+
+	// copy nil slices and maps as empty ones
+	if opt.NilAsEmpty && to.Kind() == from.Kind() && (from.Kind() == reflect.Slice || from.Kind() == reflect.Map) && from.IsNil() {
+		if from.Kind() == reflect.Slice {
+			to.Set(reflect.MakeSlice(to.Type(), 0, 0))
+		} else {
+			to.Set(reflect.MakeMap(to.Type()))
+		}
+		return true, nil
+	}
+
+	if opt.SliceStrategy != SliceOverwrite && from.Kind() == reflect.Slice && to.Kind() == reflect.Slice {
+		return true, mergeSlice(to, from, opt, converters)
+	}
+
+	// merge maps key by key
+	if opt.MergeMaps && from.Kind() == reflect.Map && to.Kind() == reflect.Map && !from.IsNil() && !to.IsNil() {
+		return false, nil
+	}
+
 	if to.Kind() == reflect.Ptr {
 		// set `to` to nil if from is nil
 		if from.Kind() == reflect.Ptr && from.IsNil() {
@@ -588,7 +1517,10 @@ func set(to, from reflect.Value, deepCopy bool, converters map[converterPair]Typ
 			if fromValuer, ok := driverValuer(from); ok {
 				v, err := fromValuer.Value()
 				if err != nil {
-					return true, nil
+					if opt.IgnoreSQLErrors {
+						return true, nil
+					}
+					return false, &ConversionError{Src: from.Type(), Dst: to.Type(), Err: err}
 				}
 				// if `from` is not valid do nothing with `to`
 				if v == nil {
@@ -605,7 +1537,14 @@ func set(to, from reflect.Value, deepCopy bool, converters map[converterPair]Typ
 		to = to.Elem()
 	}
 
-	if deepCopy {
+	// try TextMarshaler and TextUnmarshaler
+	if opt.TextMarshaling {
+		if ok, err := copyText(to, from); err != nil || ok {
+			return ok, err
+		}
+	}
+
+	if opt.DeepCopy {
 		toKind := to.Kind()
 		if toKind == reflect.Interface && to.IsNil() {
 			if reflect.TypeOf(from.Interface()) != nil {
@@ -617,11 +1556,28 @@ func set(to, from reflect.Value, deepCopy bool, converters map[converterPair]Typ
 			to.Set(reflect.Zero(to.Type()))
 			return true, nil
 		}
-		if _, ok := to.Addr().Interface().(sql.Scanner); !ok && (toKind == reflect.Struct || toKind == reflect.Map || toKind == reflect.Slice) {
+		if _, ok := to.Addr().Interface().(sql.Scanner); !ok && (toKind == reflect.Struct || toKind == reflect.Map || toKind == reflect.Slice || toKind == reflect.Array) {
 			return false, nil
 		}
 	}
 
+	// try parse or format strings
+	if opt.WeaklyTyped {
+		if ok, err := coerce(to, from); err != nil || ok {
+			return ok, err
+		}
+	}
+
+	// copy structs field by field in secure mode, as well as the slices, arrays and maps holding them, so only allowed fields are set
+	if opt.secure() && hasFields(to.Type()) && hasFields(from.Type()) {
+		return false, nil
+	}
+
+	// copy arrays element by element, converting slices to arrays could panic
+	if isArrayCopy(to, from) && !from.Type().AssignableTo(to.Type()) {
+		return false, nil
+	}
+
 	// try convert directly
 	if from.Type().ConvertibleTo(to.Type()) {
 		to.Set(from.Convert(to.Type()))
@@ -647,6 +1603,10 @@ func set(to, from reflect.Value, deepCopy bool, converters map[converterPair]Typ
 		if err == nil {
 			return true, nil
 		}
+		// struct sources may still be copied field by field
+		if !opt.IgnoreSQLErrors && from.Kind() != reflect.Struct {
+			return false, &ConversionError{Src: from.Type(), Dst: to.Type(), Err: err}
+		}
 	}
 
 	// try Valuer
@@ -655,7 +1615,10 @@ func set(to, from reflect.Value, deepCopy bool, converters map[converterPair]Typ
 		// sql.NullString -> string
 		v, err := fromValuer.Value()
 		if err != nil {
-			return false, nil
+			if opt.IgnoreSQLErrors {
+				return false, nil
+			}
+			return false, &ConversionError{Src: from.Type(), Dst: to.Type(), Err: err}
 		}
 		// if `from` is not valid do nothing with `to`
 		if v == nil {
@@ -675,12 +1638,494 @@ func set(to, from reflect.Value, deepCopy bool, converters map[converterPair]Typ
 
 	// from is ptr
 	if from.Kind() == reflect.Ptr {
-		return set(to, from.Elem(), deepCopy, converters)
+		return set(to, from.Elem(), opt, converters)
 	}
 
 	return false, nil
 }
 
+// copyWrapper copies between optional value wrappers and the values they hold, e.g.
+// `Optional[Inner]` -> `*Inner`, `Inner` -> `Optional[InnerDTO]` or `sql.Null[T]` -> `Optional[T]`.
+func copyWrapper(to, from reflect.Value, opt Option, converters map[converterPair]TypeConverter) (bool, error) {
+	fromWrapper, _, isFromWrapper := opt.wrapper(from.Type())
+	toWrapper, toElem, isToWrapper := opt.wrapper(to.Type())
+	if (!isFromWrapper && !isToWrapper) || from.Type() == to.Type() {
+		return false, nil
+	}
+
+	// unwrap `from`, an invalid wrapper resets `to`
+	if isFromWrapper {
+		value, valid := fromWrapper.Unwrap(from)
+		if !valid {
+			to.Set(reflect.Zero(to.Type()))
+			return true, nil
+		}
+		from = value
+	}
+	if from.Kind() == reflect.Ptr && from.IsNil() {
+		to.Set(reflect.Zero(to.Type()))
+		return true, nil
+	}
+
+	if !isToWrapper {
+		return true, copyValue(to, from, opt, converters)
+	}
+
+	value := indirect(reflect.New(toElem))
+	if err := copyValue(value, from, opt, converters); err != nil {
+		return false, err
+	}
+	toWrapper.Wrap(to, value)
+	return true, nil
+}
+
+// copyValue sets `to` from `from`, copying structs, maps and slices with their mapping rules.
+func copyValue(to, from reflect.Value, opt Option, converters map[converterPair]TypeConverter) error {
+	isSet, err := set(to, from, opt, converters)
+	if err != nil || isSet {
+		return err
+	}
+	return copier(to.Addr().Interface(), from.Interface(), opt)
+}
+
+// mergeValue copies `from` into the existing value `to`, merging structs field by field.
+func mergeValue(to, from reflect.Value, opt Option, converters map[converterPair]TypeConverter) error {
+	if canMerge(to, from) {
+		return copier(to.Addr().Interface(), from.Interface(), opt)
+	}
+	return copyValue(to, from, opt, converters)
+}
+
+// isArrayCopy reports whether `from` is an array copied into an array or a slice, or a slice copied into an array.
+func isArrayCopy(to, from reflect.Value) bool {
+	switch from.Kind() {
+	case reflect.Array:
+		return to.Kind() == reflect.Array || to.Kind() == reflect.Slice
+	case reflect.Slice:
+		return to.Kind() == reflect.Array
+	}
+	return false
+}
+
+// arrayToSlice returns a slice holding the elements of the array `from`.
+func arrayToSlice(from reflect.Value) reflect.Value {
+	slice := reflect.MakeSlice(reflect.SliceOf(from.Type().Elem()), from.Len(), from.Len())
+	reflect.Copy(slice, from)
+	return slice
+}
+
+// copyArray copies the slice or array `from` into the array `to` element by element, following opt.ArrayLength.
+func copyArray(to, from reflect.Value, opt Option, converters map[converterPair]TypeConverter) error {
+	if from.Len() != to.Len() {
+		longer := from.Len() > to.Len()
+		if opt.ArrayLength == ArrayExact || (longer && opt.ArrayLength == ArrayPad) || (!longer && opt.ArrayLength == ArrayTruncate) {
+			return fmt.Errorf("%w: %d elements into %v", ErrArrayLength, from.Len(), to.Type())
+		}
+	}
+
+	for i := 0; i < to.Len(); i++ {
+		if i >= from.Len() {
+			to.Index(i).Set(reflect.Zero(to.Type().Elem()))
+			continue
+		}
+		if err := copyValue(to.Index(i), from.Index(i), opt, converters); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sliceToMap copies a slice of structs into a map keyed by their key field.
+func sliceToMap(to, from reflect.Value, opt Option, converters map[converterPair]TypeConverter) error {
+	keyField, ok := opt.sliceKey(from.Type().Elem())
+	if !ok {
+		if keyField, ok = opt.sliceKey(to.Type().Elem()); !ok {
+			return fmt.Errorf("%w: %v", ErrSliceKeyNotFound, from.Type().Elem())
+		}
+	}
+
+	// key the source elements by their key field converted to the destination key type,
+	// then copy them with the map rules
+	keyed := reflect.MakeMapWithSize(reflect.MapOf(to.Type().Key(), from.Type().Elem()), from.Len())
+	for i := 0; i < from.Len(); i++ {
+		elem := indirect(from.Index(i))
+		if !elem.IsValid() {
+			continue
+		}
+		keyValue := fieldByName(elem, keyField, opt.CaseSensitive)
+		if !keyValue.IsValid() {
+			return fmt.Errorf("%w: %v has no field %s", ErrSliceKeyNotFound, elem.Type(), keyField)
+		}
+		key := reflect.New(to.Type().Key()).Elem()
+		if err := copyValue(key, keyValue, opt, converters); err != nil {
+			return err
+		}
+		if keyed.MapIndex(key).IsValid() {
+			return fmt.Errorf("%w: %v", ErrDuplicateKey, key.Interface())
+		}
+		keyed.SetMapIndex(key, from.Index(i))
+	}
+	return copier(to.Addr().Interface(), keyed.Interface(), opt)
+}
+
+// mapToSlice copies the values of a map into a slice, sorted by opt.MapKeyLess or by their keys.
+func mapToSlice(to, from reflect.Value, opt Option) error {
+	keys := from.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		if opt.MapKeyLess != nil {
+			return opt.MapKeyLess(keys[i].Interface(), keys[j].Interface())
+		}
+		return lessValue(keys[i], keys[j])
+	})
+
+	values := reflect.MakeSlice(reflect.SliceOf(from.Type().Elem()), 0, len(keys))
+	for _, k := range keys {
+		values = reflect.Append(values, from.MapIndex(k))
+	}
+	return copier(to.Addr().Interface(), values.Interface(), opt)
+}
+
+func lessValue(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	}
+	return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+}
+
+// mergeMapValue merges `from` into a copy of the existing map value, returning an invalid value when it can't be merged.
+func mergeMapValue(existing, from reflect.Value, opt Option, converters map[converterPair]TypeConverter) (reflect.Value, error) {
+	value := reflect.New(existing.Type()).Elem()
+	value.Set(existing)
+
+	target := value
+	isInterface := value.Kind() == reflect.Interface
+	if isInterface {
+		// merge into the dynamic value, e.g. nested maps of map[string]interface{}
+		if value.IsNil() {
+			return reflect.Value{}, nil
+		}
+		target = reflect.New(value.Elem().Type()).Elem()
+		target.Set(value.Elem())
+		if from.Kind() == reflect.Interface {
+			from = from.Elem()
+		}
+		if !from.IsValid() || from.Type() != target.Type() {
+			return reflect.Value{}, nil
+		}
+	}
+
+	isMap := target.Kind() == reflect.Map && indirect(from).Kind() == reflect.Map
+	if !isMap && !canMerge(target, from) {
+		return reflect.Value{}, nil
+	}
+	if err := copier(target.Addr().Interface(), from.Interface(), opt); err != nil {
+		return reflect.Value{}, err
+	}
+
+	if isInterface {
+		value.Set(target)
+	}
+	return value, nil
+}
+
+// mergeSlice copies the slice `from` into the slice `to` following opt.SliceStrategy.
+func mergeSlice(to, from reflect.Value, opt Option, converters map[converterPair]TypeConverter) error {
+	elemType := to.Type().Elem()
+	newElem := func(i int) (reflect.Value, error) {
+		elem := reflect.New(elemType).Elem()
+		return elem, copyValue(elem, from.Index(i), opt, converters)
+	}
+
+	switch opt.SliceStrategy {
+	case SliceReplace:
+		if from.IsNil() {
+			to.Set(reflect.Zero(to.Type()))
+			return nil
+		}
+		slice := reflect.MakeSlice(to.Type(), from.Len(), from.Len())
+		for i := 0; i < from.Len(); i++ {
+			if err := copyValue(slice.Index(i), from.Index(i), opt, converters); err != nil {
+				return err
+			}
+		}
+		to.Set(slice)
+	case SliceAppend:
+		for i := 0; i < from.Len(); i++ {
+			elem, err := newElem(i)
+			if err != nil {
+				return err
+			}
+			to.Set(reflect.Append(to, elem))
+		}
+	case SliceMergeIndex:
+		for i := 0; i < from.Len(); i++ {
+			if i < to.Len() {
+				if err := mergeValue(to.Index(i), from.Index(i), opt.at(strconv.Itoa(i)), converters); err != nil {
+					return err
+				}
+				continue
+			}
+			elem, err := newElem(i)
+			if err != nil {
+				return err
+			}
+			to.Set(reflect.Append(to, elem))
+		}
+	case SliceMergeKey:
+		keyField, ok := opt.sliceKey(elemType)
+		if !ok {
+			if keyField, ok = opt.sliceKey(from.Type().Elem()); !ok {
+				return fmt.Errorf("%w: %v", ErrSliceKeyNotFound, elemType)
+			}
+		}
+
+		keyOf := func(v reflect.Value) (interface{}, error) {
+			if v = indirect(v); !v.IsValid() {
+				return nil, nil
+			}
+			key := fieldByName(v, keyField, opt.CaseSensitive)
+			if !key.IsValid() {
+				return nil, fmt.Errorf("%w: %v has no field %s", ErrSliceKeyNotFound, v.Type(), keyField)
+			}
+			if !key.Type().Comparable() {
+				return nil, fmt.Errorf("%w: %s of %v is not comparable", ErrSliceKeyNotFound, keyField, v.Type())
+			}
+			return key.Interface(), nil
+		}
+
+		// index destination elements by key, converting source keys to the destination key type
+		indexes := map[interface{}]int{}
+		var keyType reflect.Type
+		for i := 0; i < to.Len(); i++ {
+			key, err := keyOf(to.Index(i))
+			if err != nil {
+				return err
+			}
+			if key != nil {
+				indexes[key] = i
+				keyType = reflect.TypeOf(key)
+			}
+		}
+
+		// source keys are checked before merging, so duplicate keys leave the destination untouched
+		keys := make([]interface{}, from.Len())
+		sourceKeys := map[interface{}]bool{}
+		for i := range keys {
+			key, err := keyOf(from.Index(i))
+			if err != nil {
+				return err
+			}
+			if key != nil && keyType != nil && reflect.TypeOf(key) != keyType && reflect.TypeOf(key).ConvertibleTo(keyType) {
+				key = reflect.ValueOf(key).Convert(keyType).Interface()
+			}
+			if key != nil && sourceKeys[key] {
+				return fmt.Errorf("%w: %v", ErrDuplicateKey, key)
+			}
+			sourceKeys[key], keys[i] = key != nil, key
+		}
+
+		seen := map[int]bool{}
+		length := to.Len()
+		for i, key := range keys {
+			if j, ok := indexes[key]; ok && key != nil {
+				if err := mergeValue(to.Index(j), from.Index(i), opt.at(strconv.Itoa(j)), converters); err != nil {
+					return err
+				}
+				seen[j] = true
+				continue
+			}
+			elem, err := newElem(i)
+			if err != nil {
+				return err
+			}
+			to.Set(reflect.Append(to, elem))
+		}
+
+		if opt.DeleteMissing {
+			slice := reflect.MakeSlice(to.Type(), 0, to.Len())
+			for i := 0; i < to.Len(); i++ {
+				if i >= length || seen[i] {
+					slice = reflect.Append(slice, to.Index(i))
+				}
+			}
+			to.Set(slice)
+		}
+	}
+	return nil
+}
+
+// copyText copies `from` into `to` with MarshalText when `to` is a string or []byte,
+// or with UnmarshalText when `from` is a string or []byte.
+func copyText(to, from reflect.Value) (bool, error) {
+	if from.Type().AssignableTo(to.Type()) {
+		return false, nil
+	}
+
+	if isText(to.Type()) {
+		if from.Kind() == reflect.Ptr && from.IsNil() {
+			return false, nil
+		}
+		if marshaler, ok := textMarshaler(from); ok {
+			text, err := marshaler.MarshalText()
+			if err != nil {
+				return false, &ConversionError{Src: from.Type(), Dst: to.Type(), Err: err}
+			}
+			if to.Kind() == reflect.String {
+				to.SetString(string(text))
+			} else {
+				to.SetBytes(text)
+			}
+			return true, nil
+		}
+	}
+
+	if isText(from.Type()) && to.CanAddr() {
+		if unmarshaler, ok := to.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			var text []byte
+			if from.Kind() == reflect.String {
+				text = []byte(from.String())
+			} else {
+				text = from.Bytes()
+			}
+			if err := unmarshaler.UnmarshalText(text); err != nil {
+				return false, &ConversionError{Src: from.Type(), Dst: to.Type(), Err: err}
+			}
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func isText(t reflect.Type) bool {
+	return t.Kind() == reflect.String || (t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8)
+}
+
+func textMarshaler(v reflect.Value) (i encoding.TextMarshaler, ok bool) {
+	if !v.CanAddr() {
+		i, ok = v.Interface().(encoding.TextMarshaler)
+		return
+	}
+
+	i, ok = v.Addr().Interface().(encoding.TextMarshaler)
+	return
+}
+
+// coercible reports whether coerce is able to copy a value of type from into type to.
+func coercible(from, to reflect.Type) bool {
+	switch {
+	case from.Kind() == reflect.String:
+		return isNumberOrBool(to.Kind())
+	case to.Kind() == reflect.String:
+		return isNumberOrBool(from.Kind())
+	}
+	return false
+}
+
+func isNumberOrBool(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Bool:
+		return true
+	}
+	return false
+}
+
+// coerce parses a string into a number or bool, or formats a number or bool into a string.
+func coerce(to, from reflect.Value) (bool, error) {
+	if from.Kind() == reflect.String {
+		str := from.String()
+		if to.Type() == durationType {
+			if str == "" {
+				to.SetInt(0)
+				return true, nil
+			}
+			d, err := time.ParseDuration(str)
+			if err != nil {
+				return false, &ConversionError{Src: from.Type(), Dst: to.Type(), Err: fmt.Errorf("%w: %v", ErrCoerceFailed, err)}
+			}
+			to.SetInt(int64(d))
+			return true, nil
+		}
+
+		var err error
+		switch to.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			var i int64
+			if str != "" {
+				i, err = strconv.ParseInt(str, 10, to.Type().Bits())
+			}
+			if err == nil {
+				to.SetInt(i)
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			var u uint64
+			if str != "" {
+				u, err = strconv.ParseUint(str, 10, to.Type().Bits())
+			}
+			if err == nil {
+				to.SetUint(u)
+			}
+		case reflect.Float32, reflect.Float64:
+			var f float64
+			if str != "" {
+				f, err = strconv.ParseFloat(str, to.Type().Bits())
+			}
+			if err == nil {
+				to.SetFloat(f)
+			}
+		case reflect.Bool:
+			var b bool
+			if str != "" {
+				b, err = strconv.ParseBool(str)
+			}
+			if err == nil {
+				to.SetBool(b)
+			}
+		default:
+			return false, nil
+		}
+		if err != nil {
+			return false, &ConversionError{Src: from.Type(), Dst: to.Type(), Err: fmt.Errorf("%w: %v", ErrCoerceFailed, err)}
+		}
+		return true, nil
+	}
+
+	if to.Kind() != reflect.String {
+		return false, nil
+	}
+
+	var str string
+	switch from.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if from.Type() == durationType {
+			str = time.Duration(from.Int()).String()
+		} else {
+			str = strconv.FormatInt(from.Int(), 10)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		str = strconv.FormatUint(from.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		str = strconv.FormatFloat(from.Float(), 'f', -1, from.Type().Bits())
+	case reflect.Bool:
+		str = strconv.FormatBool(from.Bool())
+	default:
+		return false, nil
+	}
+	to.SetString(str)
+	return true, nil
+}
+
 // lookupAndCopyWithConverter looks up the type pair, on success the TypeConverter Fn func is called to copy src to dst field.
 func lookupAndCopyWithConverter(to, from reflect.Value, converters map[converterPair]TypeConverter) (copied bool, err error) {
 	pair := converterPair{
@@ -707,8 +2152,8 @@ func lookupAndCopyWithConverter(to, from reflect.Value, converters map[converter
 	return false, nil
 }
 
-// parseTags Parses struct tags and returns uint8 bit flags.
-func parseTags(tag string) (flg uint8, name string, err error) {
+// parseTags Parses struct tags and returns uint16 bit flags.
+func parseTags(tag string) (flg uint16, name string, err error) {
 	for _, t := range strings.Split(tag, ",") {
 		switch t {
 		case "-":
@@ -720,8 +2165,24 @@ func parseTags(tag string) (flg uint8, name string, err error) {
 			flg = flg | tagNoPanic
 		case "override":
 			flg = flg | tagOverride
+		case "keep":
+			flg = flg | tagKeep
+		case "omitnil":
+			flg = flg | tagOmitNil
+		case "key":
+			flg = flg | tagKey
+		case "bind":
+			flg = flg | tagBind
+		case "sensitive":
+			flg = flg | tagSensitive
 		default:
-			if unicode.IsUpper([]rune(t)[0]) {
+			if strings.HasPrefix(t, "default=") {
+				flg = flg | tagDefault
+			} else if strings.HasPrefix(t, "transform=") {
+				flg = flg | tagTransform
+			} else if strings.HasPrefix(t, "from=") {
+				flg = flg | tagFrom
+			} else if unicode.IsUpper([]rune(t)[0]) {
 				name = strings.TrimSpace(t)
 			} else {
 				err = ErrFieldNameTagStartNotUpperCase
@@ -734,7 +2195,7 @@ func parseTags(tag string) (flg uint8, name string, err error) {
 // getTagFlags Parses struct tags for bit flags, field name.
 func getFlags(dest, src reflect.Value, toType, fromType reflect.Type) (flags, error) {
 	flgs := flags{
-		BitFlags: map[string]uint8{},
+		BitFlags: map[string]uint16{},
 		SrcNames: tagNameMapping{
 			FieldNameToTag: map[string]string{},
 			TagToFieldName: map[string]string{},
@@ -743,6 +2204,10 @@ func getFlags(dest, src reflect.Value, toType, fromType reflect.Type) (flags, er
 			FieldNameToTag: map[string]string{},
 			TagToFieldName: map[string]string{},
 		},
+		Transforms: map[string][]string{},
+		Sources:    map[string][]string{},
+		Defaults:   map[string]string{},
+		Converters: map[string]TypeConverter{},
 	}
 
 	var toTypeFields, fromTypeFields []reflect.StructField
@@ -765,6 +2230,12 @@ func getFlags(dest, src reflect.Value, toType, fromType reflect.Type) (flags, er
 				flgs.DestNames.FieldNameToTag[field.Name] = name
 				flgs.DestNames.TagToFieldName[name] = field.Name
 			}
+			if transforms, ok := tagOption(tags, "transform"); ok && flgs.BitFlags[field.Name]&tagTransform != 0 {
+				flgs.Transforms[field.Name] = strings.Split(transforms, "|")
+			}
+			if sources, ok := tagOption(tags, "from"); ok && flgs.BitFlags[field.Name]&tagFrom != 0 {
+				flgs.Sources[field.Name] = strings.Split(sources, "|")
+			}
 		}
 	}
 
@@ -788,7 +2259,7 @@ func getFlags(dest, src reflect.Value, toType, fromType reflect.Type) (flags, er
 }
 
 // checkBitFlags Checks flags for error or panic conditions.
-func checkBitFlags(flagsList map[string]uint8) (err error) {
+func checkBitFlags(flagsList map[string]uint16) (err error) {
 	// Check flag conditions were met
 	for name, flgs := range flagsList {
 		if flgs&hasCopied == 0 {
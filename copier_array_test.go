@@ -0,0 +1,125 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type Point struct {
+	X, Y int
+}
+
+type PointDTO struct {
+	X, Y int64
+}
+
+func TestCopyArrayOfStructs(t *testing.T) {
+	type Shape struct {
+		Corners [3]Point
+	}
+
+	type ShapeDTO struct {
+		Corners [3]PointDTO
+	}
+
+	var dto ShapeDTO
+	if err := copier.Copy(&dto, &Shape{Corners: [3]Point{{1, 2}, {3, 4}, {5, 6}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [3]PointDTO{{1, 2}, {3, 4}, {5, 6}}
+	if dto.Corners != expected {
+		t.Errorf("expected %+v, got %+v", expected, dto.Corners)
+	}
+}
+
+func TestCopyArrayAndSlice(t *testing.T) {
+	var points []PointDTO
+	if err := copier.Copy(&points, [2]Point{{1, 2}, {3, 4}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(points, []PointDTO{{1, 2}, {3, 4}}) {
+		t.Errorf("unexpected slice: %+v", points)
+	}
+
+	type Vector struct {
+		Values []float32
+	}
+
+	type FixedVector struct {
+		Values [3]float64
+	}
+
+	var fixed FixedVector
+	if err := copier.Copy(&fixed, &Vector{Values: []float32{1, 2}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fixed.Values != [3]float64{1, 2, 0} {
+		t.Errorf("expected padded array, got %v", fixed.Values)
+	}
+
+	if err := copier.Copy(&fixed, &Vector{Values: []float32{1, 2, 3, 4}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fixed.Values != [3]float64{1, 2, 3} {
+		t.Errorf("expected truncated array, got %v", fixed.Values)
+	}
+
+	var vector Vector
+	if err := copier.Copy(&vector, &fixed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(vector.Values, []float32{1, 2, 3}) {
+		t.Errorf("unexpected slice: %v", vector.Values)
+	}
+}
+
+func TestArrayLengthPolicy(t *testing.T) {
+	tests := []struct {
+		policy  copier.ArrayLengthPolicy
+		from    []int
+		wantErr bool
+	}{
+		{copier.ArrayFit, []int{1, 2, 3, 4}, false},
+		{copier.ArrayTruncate, []int{1, 2, 3, 4}, false},
+		{copier.ArrayTruncate, []int{1}, true},
+		{copier.ArrayPad, []int{1}, false},
+		{copier.ArrayPad, []int{1, 2, 3, 4}, true},
+		{copier.ArrayExact, []int{1, 2, 3}, false},
+		{copier.ArrayExact, []int{1, 2}, true},
+	}
+
+	for _, tt := range tests {
+		var array [3]int
+		err := copier.CopyWithOption(&array, tt.from, copier.Option{ArrayLength: tt.policy})
+		if tt.wantErr != errors.Is(err, copier.ErrArrayLength) {
+			t.Errorf("policy %v with %v: unexpected error %v", tt.policy, tt.from, err)
+		}
+	}
+}
+
+func TestDeepCopyArrayOfPointers(t *testing.T) {
+	type Path struct {
+		Points [2]*Point
+	}
+
+	path := Path{Points: [2]*Point{{1, 2}, {3, 4}}}
+
+	var shallow Path
+	if err := copier.Copy(&shallow, &path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shallow.Points[0] != path.Points[0] {
+		t.Error("expected pointers to be shared without DeepCopy")
+	}
+
+	var deep Path
+	if err := copier.CopyWithOption(&deep, &path, copier.Option{DeepCopy: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deep.Points[0] == path.Points[0] || *deep.Points[0] != *path.Points[0] || *deep.Points[1] != *path.Points[1] {
+		t.Errorf("expected pointers to be deep copied: %+v", deep.Points)
+	}
+}
@@ -0,0 +1,129 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type AtomicSettings struct {
+	Theme string
+	Flags map[string]bool
+}
+
+type AtomicAccount struct {
+	Name     string
+	Tags     []string
+	Settings *AtomicSettings
+	Balance  int
+	cache    string
+}
+
+type AtomicAccountInput struct {
+	Name     string
+	Tags     []string
+	Settings AtomicSettings
+	Balance  string
+}
+
+func newAtomicAccount() AtomicAccount {
+	return AtomicAccount{
+		Name:     "jinzhu",
+		Tags:     []string{"a", "b"},
+		Settings: &AtomicSettings{Theme: "light", Flags: map[string]bool{"beta": false}},
+		Balance:  10,
+		cache:    "cached",
+	}
+}
+
+func TestAtomicCopy(t *testing.T) {
+	input := AtomicAccountInput{
+		Name:     "new",
+		Tags:     []string{"c"},
+		Settings: AtomicSettings{Theme: "dark", Flags: map[string]bool{"beta": true}},
+		Balance:  "not a number",
+	}
+
+	account := newAtomicAccount()
+	settings := account.Settings
+	err := copier.CopyWithOption(&account, &input, copier.Option{WeaklyTyped: true, Atomic: true})
+	if !errors.Is(err, copier.ErrCoerceFailed) {
+		t.Fatalf("expected ErrCoerceFailed, got %v", err)
+	}
+	if !reflect.DeepEqual(account, newAtomicAccount()) || account.Settings != settings {
+		t.Errorf("expected the destination to be untouched, got %+v %+v", account, account.Settings)
+	}
+
+	input.Balance = "20"
+	if err := copier.CopyWithOption(&account, &input, copier.Option{WeaklyTyped: true, Atomic: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := AtomicAccount{
+		Name:     "new",
+		Tags:     []string{"c"},
+		Settings: &AtomicSettings{Theme: "dark", Flags: map[string]bool{"beta": true}},
+		Balance:  20,
+		cache:    "cached",
+	}
+	if !reflect.DeepEqual(account, expected) {
+		t.Errorf("expected %+v, got %+v", expected, account)
+	}
+}
+
+func TestAtomicCopyKeepsPointers(t *testing.T) {
+	type Input struct {
+		Name    string
+		Balance string
+	}
+
+	account := newAtomicAccount()
+	settings, flags, tags := account.Settings, account.Settings.Flags, account.Tags
+	if err := copier.CopyWithOption(&account, &Input{Name: "new", Balance: "20"}, copier.Option{WeaklyTyped: true, Atomic: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account.Name != "new" || account.Balance != 20 {
+		t.Errorf("unexpected result: %+v", account)
+	}
+	if account.Settings != settings || reflect.ValueOf(account.Settings.Flags).Pointer() != reflect.ValueOf(flags).Pointer() || &account.Tags[0] != &tags[0] {
+		t.Errorf("expected the fields which weren't copied to keep their pointers, maps and slices")
+	}
+
+	// values modified in place are restored in place
+	input := AtomicAccountInput{Settings: AtomicSettings{Theme: "dark"}, Tags: []string{"c", "d"}, Balance: "not a number"}
+	err := copier.CopyWithOption(&account, &input, copier.Option{WeaklyTyped: true, Atomic: true, DeepCopy: true})
+	if !errors.Is(err, copier.ErrCoerceFailed) {
+		t.Fatalf("expected ErrCoerceFailed, got %v", err)
+	}
+	if account.Settings != settings || settings.Theme != "light" || !reflect.DeepEqual(settings.Flags, map[string]bool{"beta": false}) {
+		t.Errorf("expected the settings to be restored in place, got %+v", settings)
+	}
+	if &account.Tags[0] != &tags[0] || !reflect.DeepEqual(tags, []string{"a", "b"}) {
+		t.Errorf("expected the tags to be restored in place, got %v", tags)
+	}
+}
+
+func TestAtomicCopyMust(t *testing.T) {
+	type Target struct {
+		Name string
+		ID   int `copier:"must"`
+	}
+
+	type Source struct {
+		Name string
+	}
+
+	target := Target{Name: "old", ID: 1}
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("expected a panic")
+			}
+		}()
+		copier.CopyWithOption(&target, &Source{Name: "new"}, copier.Option{Atomic: true})
+	}()
+	if target.Name != "old" {
+		t.Errorf("expected the destination to be untouched, got %+v", target)
+	}
+}
@@ -0,0 +1,115 @@
+package copier_test
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/copier"
+)
+
+type DefaultPool struct {
+	MaxIdle int `copier:"default=10"`
+}
+
+type DefaultServerConfig struct {
+	Name    string        `copier:"default=api"`
+	Timeout time.Duration `copier:"default=30s"`
+	Retries int           `copier:"default=3"`
+	Hosts   []string      `copier:"default=a|b"`
+	Ports   []int         `copier:"default=80|443"`
+	Bind    net.IP        `copier:"default=127.0.0.1"`
+	Pool    DefaultPool
+}
+
+func TestDefaultTag(t *testing.T) {
+	type Input struct {
+		Name    string
+		Retries int
+	}
+
+	var config DefaultServerConfig
+	if err := copier.Copy(&config, &Input{Name: "web"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := DefaultServerConfig{
+		Name:    "web",
+		Timeout: 30 * time.Second,
+		Hosts:   []string{"a", "b"},
+		Ports:   []int{80, 443},
+		Bind:    net.ParseIP("127.0.0.1"),
+		Pool:    DefaultPool{MaxIdle: 10},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %+v, got %+v", expected, config)
+	}
+
+	// default values don't overwrite non-zero fields
+	config = DefaultServerConfig{Timeout: time.Second}
+	if err := copier.CopyWithOption(&config, &Input{}, copier.Option{IgnoreEmpty: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Name != "api" || config.Timeout != time.Second || config.Retries != 3 {
+		t.Errorf("unexpected result: %+v", config)
+	}
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	type Input struct {
+		Name    *string
+		Retries int
+	}
+
+	tests := []struct {
+		policy  copier.DefaultPolicy
+		name    string
+		retries int
+	}{
+		{copier.DefaultIfMissing, "", 0},
+		{copier.DefaultIfNil, "api", 0},
+		{copier.DefaultIfZero, "api", 3},
+	}
+
+	for _, tt := range tests {
+		var config DefaultServerConfig
+		if err := copier.CopyWithOption(&config, &Input{}, copier.Option{Defaults: tt.policy}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.Name != tt.name || config.Retries != tt.retries {
+			t.Errorf("policy %v: expected %q and %d, got %q and %d", tt.policy, tt.name, tt.retries, config.Name, config.Retries)
+		}
+	}
+}
+
+func TestDefaultPolicyRenamed(t *testing.T) {
+	type Input struct {
+		Name *string
+	}
+	type Config struct {
+		Host string `copier:"Name,default=localhost"`
+	}
+
+	var config Config
+	if err := copier.CopyWithOption(&config, &Input{}, copier.Option{Defaults: copier.DefaultIfNil}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Host != "localhost" {
+		t.Errorf("expected the default of the renamed field, got %+v", config)
+	}
+}
+
+func TestDefaultTagError(t *testing.T) {
+	type Target struct {
+		Timeout time.Duration `copier:"default=soon"`
+	}
+
+	var target Target
+	err := copier.Copy(&target, &struct{}{})
+	var convErr *copier.ConversionError
+	if !errors.As(err, &convErr) || convErr.Field != "Timeout" {
+		t.Errorf("expected ConversionError for Timeout, got %v", err)
+	}
+}
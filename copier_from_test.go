@@ -0,0 +1,84 @@
+package copier_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type FromLegacyUser struct {
+	Nickname string
+	FullName string
+	Username string
+	Email    *string
+}
+
+func (u FromLegacyUser) Login() string {
+	return u.Username
+}
+
+func TestFromTag(t *testing.T) {
+	type UserDTO struct {
+		DisplayName string `copier:"from=Nickname|FullName|Username"`
+		Handle      string `copier:"from=Alias|Login,transform=upper"`
+		Contact     string `copier:"from=Email|Username"`
+		Nickname    string
+	}
+
+	tests := []struct {
+		user     FromLegacyUser
+		expected UserDTO
+	}{
+		{FromLegacyUser{Nickname: "jz", FullName: "Jinzhu", Username: "jinzhu"}, UserDTO{DisplayName: "jz", Handle: "JINZHU", Contact: "jinzhu", Nickname: "jz"}},
+		{FromLegacyUser{FullName: "Jinzhu", Username: "jinzhu"}, UserDTO{DisplayName: "Jinzhu", Handle: "JINZHU", Contact: "jinzhu"}},
+		{FromLegacyUser{Username: "jinzhu"}, UserDTO{DisplayName: "jinzhu", Handle: "JINZHU", Contact: "jinzhu"}},
+		{FromLegacyUser{}, UserDTO{}},
+	}
+
+	for _, tt := range tests {
+		dto := UserDTO{DisplayName: "old"}
+		if err := copier.Copy(&dto, &tt.user); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(dto, tt.expected) {
+			t.Errorf("%+v: expected %+v, got %+v", tt.user, tt.expected, dto)
+		}
+	}
+
+	// zero candidates are ignored with IgnoreEmpty
+	dto := UserDTO{DisplayName: "old"}
+	if err := copier.CopyWithOption(&dto, &FromLegacyUser{}, copier.Option{IgnoreEmpty: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dto.DisplayName != "old" {
+		t.Errorf("expected the destination to be untouched, got %+v", dto)
+	}
+}
+
+func TestFromTagMust(t *testing.T) {
+	type Target struct {
+		Name string `copier:"must,nopanic,from=Nickname|FullName"`
+	}
+
+	type Source struct {
+		FullName string
+	}
+
+	var target Target
+	if err := copier.Copy(&target, &Source{FullName: "Jinzhu"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.Name != "Jinzhu" {
+		t.Errorf("expected Jinzhu, got %q", target.Name)
+	}
+
+	// no candidate matched
+	type Other struct {
+		Name     string
+		Username string
+	}
+	if err := copier.Copy(&target, &Other{Name: "jinzhu", Username: "jinzhu"}); err == nil {
+		t.Errorf("expected an error as no candidate matched")
+	}
+}
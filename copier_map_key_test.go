@@ -0,0 +1,93 @@
+package copier_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type UUID [4]byte
+
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(u[:])), nil
+}
+
+func TestMapKeyConverter(t *testing.T) {
+	from := map[UUID]int{{1, 2, 3, 4}: 1, {5, 6, 7, 8}: 2}
+
+	var to map[string]int64
+	err := copier.CopyWithOption(&to, from, copier.Option{
+		Converters: []copier.TypeConverter{{
+			SrcType: UUID{},
+			DstType: copier.String,
+			Fn: func(src interface{}) (interface{}, error) {
+				u := src.(UUID)
+				return hex.EncodeToString(u[:]), nil
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]int64{"01020304": 1, "05060708": 2}
+	if !reflect.DeepEqual(to, expected) {
+		t.Errorf("expected %v, got %v", expected, to)
+	}
+
+	var text map[string]int
+	if err := copier.CopyWithOption(&text, from, copier.Option{TextMarshaling: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text["05060708"] != 2 {
+		t.Errorf("keys were not marshaled: %v", text)
+	}
+}
+
+func TestMapKeyNotMatch(t *testing.T) {
+	var to map[string]int
+	err := copier.Copy(&to, map[UUID]int{{1, 2, 3, 4}: 1})
+	if !errors.Is(err, copier.ErrMapKeyNotMatch) {
+		t.Errorf("expected ErrMapKeyNotMatch, got %v", err)
+	}
+}
+
+func TestMapStructKeys(t *testing.T) {
+	type Coord struct {
+		X, Y int
+	}
+
+	type CoordDTO struct {
+		X, Y int64
+	}
+
+	var to map[CoordDTO]string
+	if err := copier.Copy(&to, map[Coord]string{{1, 2}: "a", {3, 4}: "b"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[CoordDTO]string{{1, 2}: "a", {3, 4}: "b"}
+	if !reflect.DeepEqual(to, expected) {
+		t.Errorf("expected %v, got %v", expected, to)
+	}
+}
+
+func TestMapKeyCollision(t *testing.T) {
+	var to map[string]int
+	err := copier.CopyWithOption(&to, map[string]int{"Key": 1, "KEY": 2}, copier.Option{
+		Converters: []copier.TypeConverter{{
+			SrcType: copier.String,
+			DstType: copier.String,
+			Fn: func(src interface{}) (interface{}, error) {
+				return strings.ToLower(src.(string)), nil
+			},
+		}},
+	})
+	if !errors.Is(err, copier.ErrDuplicateKey) {
+		t.Errorf("expected ErrDuplicateKey, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package copier_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type Settings struct {
+	Theme  string
+	Volume int
+}
+
+func TestMergeMapsOfStructs(t *testing.T) {
+	layers := map[string]Settings{"alice": {Theme: "dark", Volume: 3}, "bob": {Theme: "light"}}
+	override := map[string]Settings{"alice": {Volume: 7}, "carol": {Theme: "blue"}}
+
+	if err := copier.CopyWithOption(&layers, override, copier.Option{MergeMaps: true, IgnoreEmpty: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]Settings{"alice": {Theme: "dark", Volume: 7}, "bob": {Theme: "light"}, "carol": {Theme: "blue"}}
+	if !reflect.DeepEqual(layers, expected) {
+		t.Errorf("expected %+v, got %+v", expected, layers)
+	}
+
+	pointers := map[string]*Settings{"alice": {Theme: "dark", Volume: 3}}
+	alice := pointers["alice"]
+	if err := copier.CopyWithOption(&pointers, map[string]*Settings{"alice": {Volume: 7}}, copier.Option{MergeMaps: true, IgnoreEmpty: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pointers["alice"] != alice || alice.Theme != "dark" || alice.Volume != 7 {
+		t.Errorf("pointer value was not merged: %+v", pointers["alice"])
+	}
+}
+
+func TestMergeNestedMaps(t *testing.T) {
+	type Config struct {
+		Values map[string]interface{}
+	}
+
+	config := Config{Values: map[string]interface{}{
+		"db":   map[string]interface{}{"host": "localhost", "port": 5432},
+		"name": "app",
+	}}
+	layer := Config{Values: map[string]interface{}{
+		"db":   map[string]interface{}{"port": 6543},
+		"name": "service",
+	}}
+
+	if err := copier.CopyWithOption(&config, &layer, copier.Option{MergeMaps: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"db":   map[string]interface{}{"host": "localhost", "port": 6543},
+		"name": "service",
+	}
+	if !reflect.DeepEqual(config.Values, expected) {
+		t.Errorf("expected %+v, got %+v", expected, config.Values)
+	}
+}
+
+func TestMergeMapsDeleteMissing(t *testing.T) {
+	type Config struct {
+		Users map[string]Settings
+	}
+
+	config := Config{Users: map[string]Settings{"alice": {Theme: "dark", Volume: 3}, "bob": {Theme: "light"}}}
+	layer := Config{Users: map[string]Settings{"alice": {Volume: 7}}}
+
+	if err := copier.CopyWithOption(&config, &layer, copier.Option{MergeMaps: true, IgnoreEmpty: true, DeleteMissing: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]Settings{"alice": {Theme: "dark", Volume: 7}}
+	if !reflect.DeepEqual(config.Users, expected) {
+		t.Errorf("expected %+v, got %+v", expected, config.Users)
+	}
+}
+
+func TestMapsAreReplacedByDefault(t *testing.T) {
+	layers := map[string]Settings{"alice": {Theme: "dark", Volume: 3}}
+	if err := copier.CopyWithOption(&layers, map[string]Settings{"alice": {Volume: 7}}, copier.Option{IgnoreEmpty: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if layers["alice"] != (Settings{Volume: 7}) {
+		t.Errorf("expected value to be replaced, got %+v", layers["alice"])
+	}
+}
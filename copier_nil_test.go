@@ -0,0 +1,101 @@
+package copier_test
+
+import (
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type PatchUser struct {
+	Name   *string
+	Active *bool
+	Age    *int
+	Tags   []string
+	Meta   map[string]string
+}
+
+type StoredUser struct {
+	Name   string
+	Active bool
+	Age    int
+	Tags   []string
+	Meta   map[string]string
+}
+
+func TestIgnoreNil(t *testing.T) {
+	active, age := false, 0
+	user := StoredUser{Name: "jinzhu", Active: true, Age: 18, Tags: []string{"admin"}, Meta: map[string]string{"a": "b"}}
+	patch := PatchUser{Active: &active, Age: &age}
+
+	if err := copier.CopyWithOption(&user, &patch, copier.Option{IgnoreNil: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if user.Name != "jinzhu" || len(user.Tags) != 1 || user.Meta["a"] != "b" {
+		t.Errorf("nil fields should be ignored: %+v", user)
+	}
+	if user.Active || user.Age != 0 {
+		t.Errorf("explicit zero values should be copied: %+v", user)
+	}
+}
+
+func TestOmitNilTag(t *testing.T) {
+	type User struct {
+		Name   *string `copier:"omitnil"`
+		Active bool    `copier:"omitnil"`
+		Tags   []string
+	}
+
+	name, active := "jinzhu", false
+	user := User{Name: &name, Active: true, Tags: []string{"admin"}}
+	if err := copier.Copy(&user, &PatchUser{Active: &active}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if user.Name != &name || user.Active || user.Tags != nil {
+		t.Errorf("unexpected result: %+v", user)
+	}
+}
+
+func TestOmitNilTagRenamed(t *testing.T) {
+	type User struct {
+		Nick *string `copier:"Name,omitnil"`
+	}
+
+	nick := "jinzhu"
+	user := User{Nick: &nick}
+	if err := copier.Copy(&user, &PatchUser{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.Nick != &nick {
+		t.Errorf("expected the nil source of the renamed field to be omitted, got %+v", user)
+	}
+}
+
+func TestNilAsEmpty(t *testing.T) {
+	var user StoredUser
+	if err := copier.Copy(&user, &StoredUser{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.Tags != nil || user.Meta != nil {
+		t.Errorf("expected nil slice and map, got %#v", user)
+	}
+
+	for _, deepCopy := range []bool{false, true} {
+		var user StoredUser
+		if err := copier.CopyWithOption(&user, &StoredUser{}, copier.Option{NilAsEmpty: true, DeepCopy: deepCopy}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if user.Tags == nil || len(user.Tags) != 0 || user.Meta == nil || len(user.Meta) != 0 {
+			t.Errorf("expected empty slice and map with DeepCopy %v, got %#v", deepCopy, user)
+		}
+	}
+
+	var tags []string
+	if err := copier.CopyWithOption(&tags, []string(nil), copier.Option{NilAsEmpty: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tags == nil {
+		t.Error("expected empty slice, got nil")
+	}
+}
@@ -0,0 +1,130 @@
+package copier_test
+
+import (
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type PoolConfig struct {
+	MaxIdle int
+	MaxOpen int
+}
+
+type DBConfig struct {
+	Host    string
+	Port    int
+	Debug   bool
+	Pool    PoolConfig
+	Replica *PoolConfig
+}
+
+func TestOverwriteZero(t *testing.T) {
+	defaults := DBConfig{
+		Host:    "localhost",
+		Port:    5432,
+		Debug:   true,
+		Pool:    PoolConfig{MaxIdle: 2, MaxOpen: 10},
+		Replica: &PoolConfig{MaxIdle: 1, MaxOpen: 5},
+	}
+
+	replica := &PoolConfig{MaxOpen: 20}
+	config := DBConfig{Host: "db.internal", Pool: PoolConfig{MaxOpen: 50}, Replica: replica}
+
+	if err := copier.CopyWithOption(&config, &defaults, copier.Option{Overwrite: copier.OverwriteZero}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if config.Host != "db.internal" || config.Port != 5432 || !config.Debug {
+		t.Errorf("defaults were not merged: %+v", config)
+	}
+	if config.Pool.MaxIdle != 2 || config.Pool.MaxOpen != 50 {
+		t.Errorf("nested struct was not merged: %+v", config.Pool)
+	}
+	if config.Replica != replica || config.Replica.MaxIdle != 1 || config.Replica.MaxOpen != 20 {
+		t.Errorf("nested pointer was not merged: %+v", config.Replica)
+	}
+}
+
+func TestOverwriteNilPointers(t *testing.T) {
+	replica := &PoolConfig{MaxOpen: 20}
+	config := DBConfig{Host: "db.internal", Replica: replica}
+	src := DBConfig{Host: "localhost", Replica: &PoolConfig{MaxIdle: 1, MaxOpen: 5}}
+
+	if err := copier.CopyWithOption(&config, &src, copier.Option{Overwrite: copier.OverwriteNilPointers}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if config.Host != "localhost" {
+		t.Errorf("expected Host to be overwritten, got %v", config.Host)
+	}
+	if config.Replica != replica || config.Replica.MaxIdle != 0 || config.Replica.MaxOpen != 20 {
+		t.Errorf("expected pointer to be kept untouched, got %+v", config.Replica)
+	}
+
+	var empty DBConfig
+	if err := copier.CopyWithOption(&empty, &src, copier.Option{Overwrite: copier.OverwriteNilPointers}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if empty.Replica == nil || empty.Replica.MaxOpen != 5 {
+		t.Errorf("expected nil pointer to be overwritten, got %+v", empty.Replica)
+	}
+}
+
+func TestOverwriteTags(t *testing.T) {
+	type Settings struct {
+		Name   string
+		Theme  string
+		Locale string
+	}
+
+	type UserSettings struct {
+		Name   string `copier:"keep"`
+		Theme  string `copier:"override"`
+		Locale string
+	}
+
+	settings := UserSettings{Name: "custom", Theme: "dark", Locale: "fr"}
+	src := Settings{Name: "default", Theme: "light", Locale: "en"}
+
+	if err := copier.Copy(&settings, &src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := UserSettings{Name: "custom", Theme: "light", Locale: "en"}
+	if settings != expected {
+		t.Errorf("expected %+v, got %+v", expected, settings)
+	}
+
+	settings = UserSettings{Theme: "dark", Locale: "fr"}
+	if err := copier.CopyWithOption(&settings, &src, copier.Option{Overwrite: copier.OverwriteZero}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = UserSettings{Name: "default", Theme: "light", Locale: "fr"}
+	if settings != expected {
+		t.Errorf("expected %+v, got %+v", expected, settings)
+	}
+}
+
+type OverwriteThemeSource struct{}
+
+func (OverwriteThemeSource) Theme() string {
+	return "dark"
+}
+
+func TestOverwriteMethodSourceKept(t *testing.T) {
+	type Settings struct {
+		Theme string
+	}
+
+	settings := Settings{Theme: "light"}
+	result, err := copier.CopyWithResult(&settings, OverwriteThemeSource{}, copier.Option{Overwrite: copier.OverwriteZero})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.Theme != "light" {
+		t.Errorf("expected Theme to be kept, got %v", settings.Theme)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != (copier.Skip{Path: "Theme", Reason: copier.SkipKept}) {
+		t.Errorf("expected Theme to be skipped as kept, got %+v", result.Skipped)
+	}
+}
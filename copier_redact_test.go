@@ -0,0 +1,104 @@
+package copier_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type RedactCredential struct {
+	Name   string
+	APIKey string `copier:"sensitive"`
+}
+
+type RedactUser struct {
+	Name        string
+	Password    string  `copier:"sensitive"`
+	Token       *string `copier:"sensitive"`
+	PIN         int     `copier:"sensitive"`
+	Credentials []RedactCredential
+	Services    map[string]*RedactCredential
+	Extra       interface{}
+}
+
+func (u RedactUser) Primary() RedactCredential {
+	return u.Credentials[0]
+}
+
+type RedactUserLog struct {
+	Name        string
+	Password    string
+	Token       *string
+	PIN         int
+	Credentials []RedactCredential
+	Services    map[string]*RedactCredential
+	Extra       interface{}
+	Primary     RedactCredential
+}
+
+func newRedactUser() RedactUser {
+	token := "token"
+	return RedactUser{
+		Name:        "jinzhu",
+		Password:    "secret",
+		Token:       &token,
+		PIN:         1234,
+		Credentials: []RedactCredential{{Name: "github", APIKey: "gh-key"}},
+		Services:    map[string]*RedactCredential{"s3": {Name: "aws", APIKey: "aws-key"}},
+		Extra:       RedactCredential{Name: "extra", APIKey: "extra-key"},
+	}
+}
+
+func TestRedact(t *testing.T) {
+	user := newRedactUser()
+
+	var log RedactUserLog
+	if err := copier.CopyWithOption(&log, &user, copier.Option{Redact: copier.RedactMask}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := RedactUserLog{
+		Name:        "jinzhu",
+		Password:    "***",
+		Credentials: []RedactCredential{{Name: "github", APIKey: "***"}},
+		Services:    map[string]*RedactCredential{"s3": {Name: "aws", APIKey: "***"}},
+		Extra:       RedactCredential{Name: "extra", APIKey: "***"},
+		Primary:     RedactCredential{Name: "github", APIKey: "***"},
+	}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("expected %+v, got %+v", expected, log)
+	}
+	if !reflect.DeepEqual(user, newRedactUser()) {
+		t.Errorf("redaction shouldn't modify the source, got %+v", user)
+	}
+
+	log = RedactUserLog{}
+	if err := copier.CopyWithOption(&log, &user, copier.Option{Redact: copier.RedactZero}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.Password != "" || log.Credentials[0].APIKey != "" || log.Services["s3"].APIKey != "" || log.Primary.APIKey != "" {
+		t.Errorf("expected sensitive values to be zero, got %+v", log)
+	}
+
+	// sensitive fields are copied as is by default
+	log = RedactUserLog{}
+	if err := copier.Copy(&log, &user); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.Password != "secret" || log.Credentials[0].APIKey != "gh-key" {
+		t.Errorf("expected sensitive values to be copied, got %+v", log)
+	}
+}
+
+func TestRedactSlices(t *testing.T) {
+	users := []RedactUser{newRedactUser(), newRedactUser()}
+
+	var logs []RedactUserLog
+	if err := copier.CopyWithOption(&logs, &users, copier.Option{Redact: copier.RedactMask, DeepCopy: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logs) != 2 || logs[1].Password != "***" || logs[1].Services["s3"].APIKey != "***" {
+		t.Errorf("unexpected result: %+v", logs)
+	}
+}
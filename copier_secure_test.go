@@ -0,0 +1,170 @@
+package copier_test
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type Account struct {
+	Name     string `copier:"bind"`
+	Email    string `copier:"bind"`
+	IsAdmin  bool
+	Balance  int
+	TenantID string
+}
+
+func TestSecureCopy(t *testing.T) {
+	type UpdateAccountRequest struct {
+		Name    string
+		Email   string
+		IsAdmin bool
+		Balance int
+	}
+
+	account := Account{Name: "old", TenantID: "t1"}
+	req := UpdateAccountRequest{Name: "new", Email: "new@example.com", IsAdmin: true, Balance: 1000}
+
+	err := copier.CopyWithOption(&account, &req, copier.Option{Secure: true})
+	if !errors.Is(err, copier.ErrFieldNotAllowed) {
+		t.Fatalf("expected ErrFieldNotAllowed, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "IsAdmin") || !strings.Contains(err.Error(), "Balance") {
+		t.Errorf("expected denied fields to be reported, got %v", err)
+	}
+
+	expected := Account{Name: "new", Email: "new@example.com", TenantID: "t1"}
+	if account != expected {
+		t.Errorf("expected %+v, got %+v", expected, account)
+	}
+}
+
+func TestSecureCopyAllow(t *testing.T) {
+	type UpdateBalanceRequest struct {
+		Name    string
+		Balance int
+	}
+
+	var account Account
+	if err := copier.CopyWithOption(&account, &UpdateBalanceRequest{Name: "name", Balance: 10}, copier.Option{Allow: []string{"Balance"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account.Name != "name" || account.Balance != 10 {
+		t.Errorf("unexpected result: %+v", account)
+	}
+}
+
+func TestSecureCopyNested(t *testing.T) {
+	type Profile struct {
+		Bio      string `copier:"bind"`
+		Verified bool
+	}
+
+	type User struct {
+		Profile Profile `copier:"bind"`
+		Name    string  `copier:"bind"`
+		IsAdmin bool
+	}
+
+	type ProfileInput struct {
+		Bio      string
+		Verified bool
+	}
+
+	type UserInput struct {
+		Profile ProfileInput
+		Name    string
+		IsAdmin bool
+	}
+
+	// fields denied in nested structs don't abort the copy, and are reported with the top-level ones
+	var user User
+	err := copier.CopyWithOption(&user, &UserInput{Profile: ProfileInput{Bio: "hi", Verified: true}, Name: "jinzhu", IsAdmin: true}, copier.Option{Secure: true})
+	if !errors.Is(err, copier.ErrFieldNotAllowed) || err.Error() != "field not allowed: Profile.Verified, IsAdmin" {
+		t.Errorf("expected Profile.Verified and IsAdmin to be denied, got %v", err)
+	}
+	if user.Profile.Bio != "hi" || user.Profile.Verified || user.Name != "jinzhu" || user.IsAdmin {
+		t.Errorf("unexpected result: %+v", user)
+	}
+}
+
+func TestSecureCopySliceElements(t *testing.T) {
+	type Item struct {
+		Name   string `copier:"bind"`
+		Secret string
+	}
+
+	type ItemInput struct {
+		Name   string
+		Secret string
+	}
+
+	type Order struct {
+		Items     []Item `copier:"bind"`
+		Converted []Item `copier:"bind"`
+	}
+
+	type OrderInput struct {
+		Items     []Item
+		Converted []ItemInput
+	}
+
+	var order Order
+	input := OrderInput{Items: []Item{{Name: "a", Secret: "s"}}, Converted: []ItemInput{{Name: "b"}, {Name: "c", Secret: "s"}}}
+	err := copier.CopyWithOption(&order, &input, copier.Option{Secure: true})
+	if !errors.Is(err, copier.ErrFieldNotAllowed) || err.Error() != "field not allowed: Items.0.Secret, Converted.0.Secret, Converted.1.Secret" {
+		t.Errorf("expected the secrets of the elements to be denied, got %v", err)
+	}
+	if order.Items[0] != (Item{Name: "a"}) || order.Converted[1] != (Item{Name: "c"}) {
+		t.Errorf("unexpected result: %+v", order)
+	}
+
+	var items []Item
+	err = copier.CopyWithOption(&items, []Item{{Name: "a", Secret: "s"}}, copier.Option{Secure: true})
+	if !errors.Is(err, copier.ErrFieldNotAllowed) || err.Error() != "field not allowed: 0.Secret" {
+		t.Errorf("expected 0.Secret to be denied, got %v", err)
+	}
+	if len(items) != 1 || items[0] != (Item{Name: "a"}) {
+		t.Errorf("unexpected result: %+v", items)
+	}
+}
+
+func TestSecureCopyMapValues(t *testing.T) {
+	var accounts map[string]Account
+	input := map[string]struct {
+		Name    string
+		IsAdmin bool
+	}{"a": {Name: "jinzhu", IsAdmin: true}}
+
+	// values with denied fields are still copied with their allowed fields, like struct fields
+	err := copier.CopyWithOption(&accounts, input, copier.Option{Secure: true})
+	var deniedErr *copier.DeniedError
+	if !errors.As(err, &deniedErr) || len(deniedErr.Fields) != 1 || deniedErr.Fields[0] != "a.IsAdmin" {
+		t.Errorf("expected a.IsAdmin to be denied, got %v", err)
+	}
+	if accounts["a"] != (Account{Name: "jinzhu"}) {
+		t.Errorf("unexpected result: %+v", accounts)
+	}
+}
+
+func TestSecureBind(t *testing.T) {
+	form := url.Values{"name": {"jinzhu"}, "isadmin": {"true"}, "balance": {"10"}}
+
+	var account Account
+	// a non-empty Allow list enables secure mode
+	err := copier.Bind(&account, form, copier.Option{Allow: []string{"Balance"}})
+	if !errors.Is(err, copier.ErrFieldNotAllowed) || !strings.Contains(err.Error(), "IsAdmin") {
+		t.Errorf("expected IsAdmin to be denied, got %v", err)
+	}
+	if account.Name != "jinzhu" || account.IsAdmin || account.Balance != 10 {
+		t.Errorf("unexpected result: %+v", account)
+	}
+
+	err = copier.Bind(&account, form, copier.Option{Secure: true})
+	if !errors.Is(err, copier.ErrFieldNotAllowed) || !strings.Contains(err.Error(), "IsAdmin") {
+		t.Errorf("expected IsAdmin to be denied, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type UserID int
+
+type KeyedUser struct {
+	ID   UserID `copier:"key"`
+	Name string
+	Role string
+}
+
+type KeyedUserDTO struct {
+	ID   UserID
+	Name string
+}
+
+func TestSliceToMap(t *testing.T) {
+	users := []KeyedUser{{ID: 2, Name: "bob"}, {ID: 1, Name: "alice", Role: "admin"}}
+
+	var byID map[UserID]KeyedUserDTO
+	if err := copier.Copy(&byID, &users); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[UserID]KeyedUserDTO{1: {ID: 1, Name: "alice"}, 2: {ID: 2, Name: "bob"}}
+	if !reflect.DeepEqual(byID, expected) {
+		t.Errorf("expected %+v, got %+v", expected, byID)
+	}
+
+	type Team struct {
+		Members []*KeyedUser
+	}
+
+	type TeamDTO struct {
+		Members map[int64]*KeyedUserDTO
+	}
+
+	var team TeamDTO
+	if err := copier.Copy(&team, &Team{Members: []*KeyedUser{{ID: 3, Name: "carol"}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if member := team.Members[3]; member == nil || member.Name != "carol" {
+		t.Errorf("unexpected members: %+v", team.Members)
+	}
+}
+
+func TestSliceToMapWithOptionKey(t *testing.T) {
+	type Item struct {
+		SKU   string
+		Count int
+	}
+
+	var bySKU map[string]Item
+	opt := copier.Option{SliceKeys: []copier.SliceKey{{Type: Item{}, Field: "SKU"}}}
+	if err := copier.CopyWithOption(&bySKU, []Item{{SKU: "a", Count: 1}, {SKU: "b", Count: 2}}, opt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bySKU) != 2 || bySKU["b"].Count != 2 {
+		t.Errorf("unexpected map: %+v", bySKU)
+	}
+
+	opt = copier.Option{SliceKeys: []copier.SliceKey{{Type: Item{}, Field: "Code"}}}
+	err := copier.CopyWithOption(&bySKU, []Item{{SKU: "a"}}, opt)
+	if !errors.Is(err, copier.ErrSliceKeyNotFound) {
+		t.Errorf("expected ErrSliceKeyNotFound, got %v", err)
+	}
+}
+
+func TestSliceToMapWithoutKey(t *testing.T) {
+	type Item struct {
+		SKU string
+	}
+
+	type Source struct {
+		Tags  []string
+		Items []Item
+		Name  string
+	}
+
+	type Target struct {
+		Tags  map[string]bool
+		Items map[string]Item
+		Name  string
+	}
+
+	// slices without a key are skipped like before slices could be copied into maps
+	var target Target
+	if err := copier.Copy(&target, &Source{Tags: []string{"a"}, Items: []Item{{SKU: "a"}}, Name: "jinzhu"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.Tags != nil || target.Items != nil || target.Name != "jinzhu" {
+		t.Errorf("unexpected result: %+v", target)
+	}
+}
+
+func TestMapToSliceWithoutKey(t *testing.T) {
+	type Source struct {
+		Tags map[string]string
+		Name string
+	}
+
+	type Target struct {
+		Tags []string
+		Name string
+	}
+
+	// maps are only copied into slices of keyed structs
+	var target Target
+	if err := copier.Copy(&target, &Source{Tags: map[string]string{"a": "b"}, Name: "jinzhu"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.Tags != nil || target.Name != "jinzhu" {
+		t.Errorf("unexpected result: %+v", target)
+	}
+}
+
+func TestSliceToMapDuplicateKeys(t *testing.T) {
+	var byID map[UserID]KeyedUserDTO
+	err := copier.Copy(&byID, []KeyedUser{{ID: 1, Name: "alice"}, {ID: 1, Name: "bob"}})
+	if !errors.Is(err, copier.ErrDuplicateKey) {
+		t.Errorf("expected ErrDuplicateKey, got %v", err)
+	}
+}
+
+func TestMapToSlice(t *testing.T) {
+	byID := map[UserID]KeyedUser{3: {ID: 3, Name: "carol"}, 1: {ID: 1, Name: "alice"}, 2: {ID: 2, Name: "bob"}}
+
+	var users []KeyedUserDTO
+	if err := copier.Copy(&users, byID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []KeyedUserDTO{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}, {ID: 3, Name: "carol"}}
+	if !reflect.DeepEqual(users, expected) {
+		t.Errorf("expected %+v, got %+v", expected, users)
+	}
+
+	var reversed []*KeyedUserDTO
+	opt := copier.Option{MapKeyLess: func(a, b interface{}) bool { return a.(UserID) > b.(UserID) }}
+	if err := copier.CopyWithOption(&reversed, byID, opt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reversed) != 3 || reversed[0].Name != "carol" || reversed[2].Name != "alice" {
+		t.Errorf("unexpected order: %+v %+v %+v", reversed[0], reversed[1], reversed[2])
+	}
+}
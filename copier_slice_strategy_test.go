@@ -0,0 +1,138 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type Host struct {
+	Name string `copier:"key"`
+	Port int
+	TLS  bool
+}
+
+type HostConfig struct {
+	Hosts []Host
+}
+
+func TestSliceReplace(t *testing.T) {
+	hosts := []Host{{Name: "a", Port: 1, TLS: true}, {Name: "b", Port: 2}}
+	config := HostConfig{Hosts: hosts}
+
+	if err := copier.CopyWithOption(&config, &HostConfig{Hosts: []Host{{Name: "c", Port: 3}}}, copier.Option{SliceStrategy: copier.SliceReplace, IgnoreEmpty: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(config.Hosts, []Host{{Name: "c", Port: 3}}) {
+		t.Errorf("unexpected hosts: %+v", config.Hosts)
+	}
+	if hosts[0].Name != "a" || !hosts[0].TLS {
+		t.Errorf("existing elements should not be modified: %+v", hosts)
+	}
+}
+
+func TestSliceAppend(t *testing.T) {
+	config := HostConfig{Hosts: []Host{{Name: "a", Port: 1}}}
+
+	if err := copier.CopyWithOption(&config, &HostConfig{Hosts: []Host{{Name: "b", Port: 2}}}, copier.Option{SliceStrategy: copier.SliceAppend}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(config.Hosts, []Host{{Name: "a", Port: 1}, {Name: "b", Port: 2}}) {
+		t.Errorf("unexpected hosts: %+v", config.Hosts)
+	}
+
+	ports := []int{1}
+	if err := copier.CopyWithOption(&ports, []int64{2, 3}, copier.Option{SliceStrategy: copier.SliceAppend}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ports, []int{1, 2, 3}) {
+		t.Errorf("unexpected ports: %v", ports)
+	}
+}
+
+func TestSliceMergeIndex(t *testing.T) {
+	config := HostConfig{Hosts: []Host{{Name: "a", Port: 1, TLS: true}, {Name: "b", Port: 2}, {Name: "c", Port: 3}}}
+	patch := HostConfig{Hosts: []Host{{Port: 10}, {Name: "bb"}}}
+
+	if err := copier.CopyWithOption(&config, &patch, copier.Option{SliceStrategy: copier.SliceMergeIndex, IgnoreEmpty: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Host{{Name: "a", Port: 10, TLS: true}, {Name: "bb", Port: 2}, {Name: "c", Port: 3}}
+	if !reflect.DeepEqual(config.Hosts, expected) {
+		t.Errorf("expected %+v, got %+v", expected, config.Hosts)
+	}
+}
+
+func TestSliceMergeKey(t *testing.T) {
+	type HostDTO struct {
+		Name string
+		Port int64
+	}
+
+	type HostConfigDTO struct {
+		Hosts []*HostDTO
+	}
+
+	config := HostConfig{Hosts: []Host{{Name: "a", Port: 1, TLS: true}, {Name: "b", Port: 2}}}
+	patch := HostConfigDTO{Hosts: []*HostDTO{{Name: "b", Port: 20}, {Name: "c", Port: 30}}}
+
+	if err := copier.CopyWithOption(&config, &patch, copier.Option{SliceStrategy: copier.SliceMergeKey}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Host{{Name: "a", Port: 1, TLS: true}, {Name: "b", Port: 20}, {Name: "c", Port: 30}}
+	if !reflect.DeepEqual(config.Hosts, expected) {
+		t.Errorf("expected %+v, got %+v", expected, config.Hosts)
+	}
+
+	if err := copier.CopyWithOption(&config, &patch, copier.Option{SliceStrategy: copier.SliceMergeKey, DeleteMissing: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = []Host{{Name: "b", Port: 20}, {Name: "c", Port: 30}}
+	if !reflect.DeepEqual(config.Hosts, expected) {
+		t.Errorf("expected %+v, got %+v", expected, config.Hosts)
+	}
+}
+
+func TestSliceMergeKeyDuplicates(t *testing.T) {
+	opt := copier.Option{SliceStrategy: copier.SliceMergeKey}
+	for _, hosts := range [][]Host{
+		{{Name: "b", Port: 20}, {Name: "b", Port: 21}},
+		{{Name: "c", Port: 30}, {Name: "c", Port: 31}},
+	} {
+		config := HostConfig{Hosts: []Host{{Name: "a", Port: 1}, {Name: "b", Port: 2}}}
+		err := copier.CopyWithOption(&config, &HostConfig{Hosts: hosts}, opt)
+		if !errors.Is(err, copier.ErrDuplicateKey) {
+			t.Errorf("expected ErrDuplicateKey for %+v, got %v", hosts, err)
+		}
+		if !reflect.DeepEqual(config.Hosts, []Host{{Name: "a", Port: 1}, {Name: "b", Port: 2}}) {
+			t.Errorf("expected the destination to be untouched, got %+v", config.Hosts)
+		}
+	}
+}
+
+func TestSliceMergeKeyOption(t *testing.T) {
+	type Item struct {
+		ID    int
+		Count int
+	}
+
+	items := []Item{{ID: 1, Count: 1}, {ID: 2, Count: 2}}
+	opt := copier.Option{
+		SliceStrategy: copier.SliceMergeKey,
+		SliceKeys:     []copier.SliceKey{{Type: Item{}, Field: "ID"}},
+	}
+	if err := copier.CopyWithOption(&items, []Item{{ID: 2, Count: 20}}, opt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(items, []Item{{ID: 1, Count: 1}, {ID: 2, Count: 20}}) {
+		t.Errorf("unexpected items: %+v", items)
+	}
+
+	err := copier.CopyWithOption(&items, []Item{{ID: 2}}, copier.Option{SliceStrategy: copier.SliceMergeKey})
+	if !errors.Is(err, copier.ErrSliceKeyNotFound) {
+		t.Errorf("expected ErrSliceKeyNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package copier_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+var errBrokenValue = errors.New("broken value")
+
+type BrokenValuer struct {
+	Data string
+}
+
+func (BrokenValuer) Value() (driver.Value, error) {
+	return nil, errBrokenValue
+}
+
+type BrokenScanner struct {
+	Data string
+}
+
+func (*BrokenScanner) Scan(src interface{}) error {
+	return errBrokenValue
+}
+
+func TestValuerErrors(t *testing.T) {
+	type Row struct {
+		Name  BrokenValuer
+		Alias BrokenValuer
+	}
+
+	type Model struct {
+		Name  string
+		Alias *string
+	}
+
+	var model Model
+	err := copier.Copy(&model, &Row{})
+
+	var convErr *copier.ConversionError
+	if !errors.As(err, &convErr) {
+		t.Fatalf("expected ConversionError, got %v", err)
+	}
+	if convErr.Field != "Name" || !errors.Is(err, errBrokenValue) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	type AliasRow struct {
+		Alias BrokenValuer
+	}
+
+	err = copier.Copy(&model, &AliasRow{})
+	if !errors.As(err, &convErr) || convErr.Field != "Alias" {
+		t.Errorf("expected ConversionError for Alias, got %v", err)
+	}
+}
+
+func TestScannerErrors(t *testing.T) {
+	type Inner struct {
+		Count sql.NullInt64
+		Data  BrokenScanner
+	}
+
+	type Row struct {
+		Inner Inner
+	}
+
+	type SrcInner struct {
+		Count string
+	}
+
+	type Src struct {
+		Inner SrcInner
+	}
+
+	var row Row
+	err := copier.Copy(&row, &Src{Inner: SrcInner{Count: "many"}})
+
+	var convErr *copier.ConversionError
+	if !errors.As(err, &convErr) {
+		t.Fatalf("expected ConversionError, got %v", err)
+	}
+	if convErr.Field != "Inner.Count" {
+		t.Errorf("expected field path %q, got %q", "Inner.Count", convErr.Field)
+	}
+
+	type DataSrc struct {
+		Data string
+	}
+
+	var dst Inner
+	if err := copier.Copy(&dst, &DataSrc{Data: "x"}); !errors.Is(err, errBrokenValue) {
+		t.Errorf("expected scanner error, got %v", err)
+	}
+}
+
+func TestIgnoreSQLErrors(t *testing.T) {
+	type Row struct {
+		Name  BrokenValuer
+		Alias BrokenValuer
+		Count string
+	}
+
+	type Model struct {
+		Name  string
+		Alias *string
+		Count sql.NullInt64
+	}
+
+	model := Model{Name: "name"}
+	if err := copier.CopyWithOption(&model, &Row{Count: "many"}, copier.Option{IgnoreSQLErrors: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if model.Name != "name" || model.Alias != nil || model.Count.Valid {
+		t.Errorf("expected fields to be left untouched, got %+v", model)
+	}
+}
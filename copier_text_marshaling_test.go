@@ -0,0 +1,134 @@
+package copier_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/copier"
+)
+
+type Status int
+
+const (
+	StatusActive Status = iota + 1
+	StatusBlocked
+)
+
+var errUnknownStatus = errors.New("unknown status")
+
+func (s Status) MarshalText() ([]byte, error) {
+	switch s {
+	case StatusActive:
+		return []byte("active"), nil
+	case StatusBlocked:
+		return []byte("blocked"), nil
+	}
+	return nil, fmt.Errorf("%w: %d", errUnknownStatus, s)
+}
+
+func (s *Status) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "active":
+		*s = StatusActive
+	case "blocked":
+		*s = StatusBlocked
+	default:
+		return fmt.Errorf("%w: %s", errUnknownStatus, text)
+	}
+	return nil
+}
+
+func TestTextMarshaling(t *testing.T) {
+	type Model struct {
+		Status    Status
+		Previous  *Status
+		CreatedAt time.Time
+	}
+
+	type DTO struct {
+		Status    string
+		Previous  []byte
+		CreatedAt string
+	}
+
+	previous := StatusBlocked
+	createdAt := time.Date(2021, 3, 5, 1, 30, 0, 0, time.UTC)
+	model := Model{Status: StatusActive, Previous: &previous, CreatedAt: createdAt}
+
+	var dto DTO
+	if err := copier.CopyWithOption(&dto, &model, copier.Option{TextMarshaling: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dto.Status != "active" || string(dto.Previous) != "blocked" || dto.CreatedAt != "2021-03-05T01:30:00Z" {
+		t.Errorf("fields were not marshaled: %+v", dto)
+	}
+
+	var copied Model
+	if err := copier.CopyWithOption(&copied, &dto, copier.Option{TextMarshaling: true, DeepCopy: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if copied.Status != StatusActive || copied.Previous == nil || *copied.Previous != StatusBlocked || !copied.CreatedAt.Equal(createdAt) {
+		t.Errorf("fields were not unmarshaled: %+v", copied)
+	}
+}
+
+func TestTextMarshalingDisabled(t *testing.T) {
+	type Model struct {
+		Status Status
+	}
+
+	type DTO struct {
+		Status string
+	}
+
+	var model Model
+	if err := copier.Copy(&model, &DTO{Status: "active"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if model.Status != 0 {
+		t.Errorf("expected text unmarshaling to be opt-in, got %v", model.Status)
+	}
+}
+
+func TestTextMarshalingTopLevel(t *testing.T) {
+	var status Status
+	if err := copier.CopyWithOption(&status, "Blocked", copier.Option{TextMarshaling: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != StatusBlocked {
+		t.Errorf("expected %v, got %v", StatusBlocked, status)
+	}
+
+	var statuses []string
+	if err := copier.CopyWithOption(&statuses, []Status{StatusActive, StatusBlocked}, copier.Option{TextMarshaling: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(statuses) != 2 || statuses[0] != "active" || statuses[1] != "blocked" {
+		t.Errorf("slice was not marshaled: %v", statuses)
+	}
+}
+
+func TestTextMarshalingErrors(t *testing.T) {
+	type Model struct {
+		Status Status
+	}
+
+	type DTO struct {
+		Status string
+	}
+
+	var model Model
+	err := copier.CopyWithOption(&model, &DTO{Status: "deleted"}, copier.Option{TextMarshaling: true})
+	if !errors.Is(err, errUnknownStatus) {
+		t.Errorf("expected unmarshal error, got %v", err)
+	}
+
+	var dto DTO
+	err = copier.CopyWithOption(&dto, &Model{Status: 42}, copier.Option{TextMarshaling: true})
+	if !errors.Is(err, errUnknownStatus) {
+		t.Errorf("expected marshal error, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type TransformUser struct {
+	Email    string
+	Name     string
+	Tags     []string
+	Password string
+	Token    *string
+	Age      string
+}
+
+func (u TransformUser) Nickname() string {
+	return "  Jinzhu "
+}
+
+func TestTransformTag(t *testing.T) {
+	type UserDTO struct {
+		Mail     string   `copier:"Email,transform=trim|lower"`
+		Name     string   `copier:"transform=trim|upper"`
+		Tags     []string `copier:"transform=trim"`
+		Password string   `copier:"transform=redact"`
+		Token    *string  `copier:"transform=redact"`
+		Nickname string   `copier:"transform=trim"`
+		Age      int      `copier:"transform=trim"`
+	}
+
+	token := "token"
+	user := TransformUser{
+		Email:    " Jinzhu@Example.com ",
+		Name:     " jinzhu",
+		Tags:     []string{" a ", "b "},
+		Password: "secret",
+		Token:    &token,
+		Age:      " 18 ",
+	}
+
+	var dto UserDTO
+	if err := copier.CopyWithOption(&dto, &user, copier.Option{WeaklyTyped: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := UserDTO{
+		Mail:     "jinzhu@example.com",
+		Name:     "JINZHU",
+		Tags:     []string{"a", "b"},
+		Password: "***",
+		Nickname: "Jinzhu",
+		Age:      18,
+	}
+	if !reflect.DeepEqual(dto, expected) {
+		t.Errorf("expected %+v, got %+v", expected, dto)
+	}
+	if user.Email != " Jinzhu@Example.com " || user.Tags[0] != " a " {
+		t.Errorf("transforms shouldn't modify the source, got %+v", user)
+	}
+}
+
+func TestRegisterTransform(t *testing.T) {
+	copier.RegisterTransform("reverse", func(v reflect.Value) (reflect.Value, error) {
+		runes := []rune(v.String())
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return reflect.ValueOf(string(runes)), nil
+	})
+
+	type Target struct {
+		Age int `copier:"transform=trim|reverse"`
+	}
+
+	// transformed values go through converters
+	opt := copier.Option{
+		Converters: []copier.TypeConverter{{
+			SrcType: copier.String,
+			DstType: copier.Int,
+			Fn: func(src interface{}) (interface{}, error) {
+				return strconv.Atoi(strings.TrimLeft(src.(string), "0"))
+			},
+		}},
+	}
+
+	var target Target
+	if err := copier.CopyWithOption(&target, &TransformUser{Age: " 810 "}, opt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.Age != 18 {
+		t.Errorf("expected 18, got %v", target.Age)
+	}
+}
+
+func TestTransformErrors(t *testing.T) {
+	type UnknownTarget struct {
+		Name string `copier:"transform=shout"`
+	}
+
+	var unknown UnknownTarget
+	if err := copier.Copy(&unknown, &TransformUser{Name: "jinzhu"}); !errors.Is(err, copier.ErrUnknownTransform) {
+		t.Errorf("expected ErrUnknownTransform, got %v", err)
+	}
+
+	type NotStringTarget struct {
+		Tags int `copier:"transform=lower"`
+	}
+
+	type Source struct {
+		Tags int
+	}
+
+	var notString NotStringTarget
+	if err := copier.Copy(&notString, &Source{Tags: 1}); !errors.Is(err, copier.ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+}
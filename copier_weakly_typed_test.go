@@ -0,0 +1,135 @@
+package copier_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/copier"
+)
+
+func TestWeaklyTypedStringToTypes(t *testing.T) {
+	type Form struct {
+		Age     string
+		Score   string
+		Active  string
+		Count   string
+		Timeout string
+		Empty   string
+	}
+
+	type Command struct {
+		Age     int
+		Score   float64
+		Active  bool
+		Count   *uint8
+		Timeout time.Duration
+		Empty   int
+	}
+
+	form := Form{Age: "42", Score: "9.5", Active: "true", Count: "7", Timeout: "1m30s"}
+	var cmd Command
+
+	if err := copier.CopyWithOption(&cmd, &form, copier.Option{WeaklyTyped: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cmd.Age != 42 || cmd.Score != 9.5 || !cmd.Active || cmd.Timeout != 90*time.Second || cmd.Empty != 0 {
+		t.Errorf("string fields were not parsed: %+v", cmd)
+	}
+	if cmd.Count == nil || *cmd.Count != 7 {
+		t.Errorf("pointer field was not parsed: %v", cmd.Count)
+	}
+}
+
+func TestWeaklyTypedTypesToString(t *testing.T) {
+	type Command struct {
+		Age     int
+		Score   float32
+		Active  bool
+		Count   *uint
+		Timeout time.Duration
+	}
+
+	type Form struct {
+		Age     string
+		Score   string
+		Active  string
+		Count   string
+		Timeout string
+	}
+
+	count := uint(7)
+	cmd := Command{Age: 42, Score: 9.5, Active: true, Count: &count, Timeout: 90 * time.Second}
+	var form Form
+
+	if err := copier.CopyWithOption(&form, &cmd, copier.Option{WeaklyTyped: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := Form{Age: "42", Score: "9.5", Active: "true", Count: "7", Timeout: "1m30s"}
+	if form != expected {
+		t.Errorf("expected %+v, got %+v", expected, form)
+	}
+}
+
+func TestWeaklyTypedDisablesRuneConversion(t *testing.T) {
+	var str string
+	if err := copier.Copy(&str, 42); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if str != "*" {
+		t.Errorf("expected rune conversion without WeaklyTyped, got %q", str)
+	}
+
+	if err := copier.CopyWithOption(&str, 42, copier.Option{WeaklyTyped: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if str != "42" {
+		t.Errorf("expected %q, got %q", "42", str)
+	}
+
+	var i int
+	if err := copier.CopyWithOption(&i, "42", copier.Option{WeaklyTyped: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if i != 42 {
+		t.Errorf("expected %v, got %v", 42, i)
+	}
+}
+
+func TestWeaklyTypedSliceAndMap(t *testing.T) {
+	var ids []int
+	if err := copier.CopyWithOption(&ids, []string{"1", "2", "3"}, copier.Option{WeaklyTyped: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Errorf("slice was not parsed: %v", ids)
+	}
+
+	labels := map[int]string{}
+	if err := copier.CopyWithOption(&labels, map[int]int{1: 10}, copier.Option{WeaklyTyped: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if labels[1] != "10" {
+		t.Errorf("map was not formatted: %v", labels)
+	}
+}
+
+func TestWeaklyTypedErrors(t *testing.T) {
+	type Form struct {
+		Age string
+	}
+
+	type Command struct {
+		Age int8
+	}
+
+	for _, age := range []string{"forty", "300"} {
+		var cmd Command
+		err := copier.CopyWithOption(&cmd, &Form{Age: age}, copier.Option{WeaklyTyped: true})
+		if !errors.Is(err, copier.ErrCoerceFailed) {
+			t.Errorf("expected ErrCoerceFailed for %q, got %v", age, err)
+		}
+	}
+}
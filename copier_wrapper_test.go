@@ -0,0 +1,168 @@
+package copier_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type NullInt struct {
+	V     int
+	Valid bool
+}
+
+type WrapperAddress struct {
+	Street string
+	City   string
+}
+
+type WrapperAddressDTO struct {
+	Street string
+	City   string
+}
+
+type OptionalAddress struct {
+	Value   WrapperAddress
+	Present bool
+}
+
+type OptionalAddressDTO struct {
+	Value   WrapperAddressDTO
+	Present bool
+}
+
+// Maybe hides its value, so it needs a custom Wrapper
+type Maybe struct {
+	value *WrapperAddressDTO
+}
+
+func (m Maybe) Get() (WrapperAddressDTO, bool) {
+	if m.value == nil {
+		return WrapperAddressDTO{}, false
+	}
+	return *m.value, true
+}
+
+var maybeWrapper = copier.Wrapper{
+	Match: func(typ reflect.Type) (reflect.Type, bool) {
+		if typ != reflect.TypeOf(Maybe{}) {
+			return nil, false
+		}
+		return reflect.TypeOf(WrapperAddressDTO{}), true
+	},
+	Unwrap: func(wrapper reflect.Value) (reflect.Value, bool) {
+		value, ok := wrapper.Interface().(Maybe).Get()
+		return reflect.ValueOf(value), ok
+	},
+	Wrap: func(wrapper reflect.Value, value reflect.Value) {
+		address := value.Interface().(WrapperAddressDTO)
+		wrapper.Set(reflect.ValueOf(Maybe{value: &address}))
+	},
+}
+
+func TestNullWrapper(t *testing.T) {
+	type Row struct {
+		Age   NullInt
+		Score NullInt
+		Rank  NullInt
+	}
+
+	type Model struct {
+		Age   *int
+		Score int
+		Rank  *int
+	}
+
+	rank := 3
+	model := Model{Rank: &rank}
+	opt := copier.Option{Wrappers: []copier.Wrapper{copier.NullWrapper}}
+	if err := copier.CopyWithOption(&model, &Row{Age: NullInt{V: 18, Valid: true}, Score: NullInt{V: 10, Valid: true}}, opt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if model.Age == nil || *model.Age != 18 || model.Score != 10 || model.Rank != nil {
+		t.Errorf("wrappers were not unwrapped: %+v", model)
+	}
+
+	var row Row
+	if err := copier.CopyWithOption(&row, &Model{Age: &rank, Score: 10}, opt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Row{Age: NullInt{V: 3, Valid: true}, Score: NullInt{V: 10, Valid: true}}
+	if row != expected {
+		t.Errorf("expected %+v, got %+v", expected, row)
+	}
+}
+
+func TestFieldWrapperWithStructs(t *testing.T) {
+	type User struct {
+		Home OptionalAddress
+		Work OptionalAddress
+	}
+
+	type UserDTO struct {
+		Home *WrapperAddressDTO
+		Work OptionalAddressDTO
+	}
+
+	opt := copier.Option{Wrappers: []copier.Wrapper{copier.FieldWrapper("Value", "Present")}}
+	user := User{
+		Home: OptionalAddress{Value: WrapperAddress{Street: "Main St", City: "Springfield"}, Present: true},
+		Work: OptionalAddress{Value: WrapperAddress{Street: "Market St", City: "Shelbyville"}, Present: true},
+	}
+
+	var dto UserDTO
+	if err := copier.CopyWithOption(&dto, &user, opt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dto.Home == nil || dto.Home.Street != "Main St" || dto.Home.City != "Springfield" {
+		t.Errorf("Home was not unwrapped: %+v", dto.Home)
+	}
+	if !dto.Work.Present || dto.Work.Value.Street != "Market St" {
+		t.Errorf("Work was not copied between wrappers: %+v", dto.Work)
+	}
+
+	var copied User
+	if err := copier.CopyWithOption(&copied, &UserDTO{}, opt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if copied.Home.Present || copied.Work.Present {
+		t.Errorf("expected invalid wrappers, got %+v", copied)
+	}
+}
+
+func TestCustomWrapper(t *testing.T) {
+	type User struct {
+		Address *WrapperAddress
+	}
+
+	type UserDTO struct {
+		Address Maybe
+	}
+
+	opt := copier.Option{Wrappers: []copier.Wrapper{maybeWrapper}}
+
+	var dto UserDTO
+	if err := copier.CopyWithOption(&dto, &User{Address: &WrapperAddress{Street: "Main St"}}, opt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if address, ok := dto.Address.Get(); !ok || address.Street != "Main St" {
+		t.Errorf("Address was not wrapped: %+v", address)
+	}
+
+	var user User
+	if err := copier.CopyWithOption(&user, &dto, opt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.Address == nil || user.Address.Street != "Main St" {
+		t.Errorf("Address was not unwrapped: %+v", user.Address)
+	}
+
+	var address WrapperAddress
+	if err := copier.CopyWithOption(&address, dto.Address, opt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if address.Street != "Main St" {
+		t.Errorf("top level wrapper was not unwrapped: %+v", address)
+	}
+}
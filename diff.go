@@ -0,0 +1,148 @@
+package copier
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Change is a difference at Path, a dotted path of field names, map keys and slice indexes, e.g. `Hosts.0.Port`
+type Change struct {
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+// Diff returns the changes copying `b` into `a` with CopyWithOption would make, `a` and `b` may be of different types.
+// Paths are named after the fields of `a`, fields tagged with `copier:"-"` are skipped. `a` isn't modified.
+func Diff(a, b interface{}, opt Option) ([]Change, error) {
+	base, copied, err := copyInto(a, b, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	diff(&changes, "", base.Elem(), copied.Elem())
+	return changes, nil
+}
+
+// copyInto copies `b` into a clone of `a`, so changes follow the copy rules, and returns it along with another clone of `a`.
+// Clones are taken with cloneValue rather than copied, as copying applies the tags of `a`, e.g. `copier:"from=Name"`.
+func copyInto(a, b interface{}, opt Option) (base, copied reflect.Value, err error) {
+	old := indirect(reflect.ValueOf(a))
+	if !old.IsValid() {
+		return base, copied, ErrInvalidCopyDestination
+	}
+
+	base, copied = reflect.New(old.Type()), reflect.New(old.Type())
+	for _, v := range []reflect.Value{base, copied} {
+		v.Elem().Set(cloneValue(old, map[uintptr]reflect.Value{}))
+	}
+	opt.DeepCopy, opt.nilMaps = true, true
+	err = CopyWithOption(copied.Interface(), b, opt)
+	return base, copied, err
+}
+
+func diff(changes *[]Change, path string, oldValue, newValue reflect.Value) {
+	changed := func() {
+		*changes = append(*changes, Change{Path: path, Old: interfaceOf(oldValue), New: interfaceOf(newValue)})
+	}
+
+	if !oldValue.IsValid() || !newValue.IsValid() {
+		if oldValue.IsValid() != newValue.IsValid() {
+			changed()
+		}
+		return
+	}
+
+	switch oldValue.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if oldValue.IsNil() || newValue.IsNil() {
+			if oldValue.IsNil() != newValue.IsNil() {
+				changed()
+			}
+			return
+		}
+		if oldValue.Elem().Type() != newValue.Elem().Type() {
+			changed()
+			return
+		}
+		diff(changes, path, oldValue.Elem(), newValue.Elem())
+	case reflect.Struct:
+		if len(deepFields(oldValue.Type())) == 0 {
+			if !reflect.DeepEqual(interfaceOf(oldValue), interfaceOf(newValue)) {
+				changed()
+			}
+			return
+		}
+		for i := 0; i < oldValue.NumField(); i++ {
+			field := oldValue.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if tags := field.Tag.Get("copier"); tags != "" {
+				if flg, _, _ := parseTags(tags); flg&tagIgnore != 0 {
+					continue
+				}
+			}
+			fieldPath := joinPath(path, field.Name)
+			if field.Anonymous && indirectKind(field.Type) == reflect.Struct {
+				fieldPath = path
+			}
+			diff(changes, fieldPath, oldValue.Field(i), newValue.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		if oldValue.Kind() == reflect.Slice && oldValue.IsNil() != newValue.IsNil() && (oldValue.Len() == 0 || newValue.Len() == 0) {
+			changed()
+			return
+		}
+		for i := 0; i < oldValue.Len() || i < newValue.Len(); i++ {
+			var oldElem, newElem reflect.Value
+			if i < oldValue.Len() {
+				oldElem = oldValue.Index(i)
+			}
+			if i < newValue.Len() {
+				newElem = newValue.Index(i)
+			}
+			diff(changes, joinPath(path, strconv.Itoa(i)), oldElem, newElem)
+		}
+	case reflect.Map:
+		if oldValue.IsNil() != newValue.IsNil() && (oldValue.Len() == 0 || newValue.Len() == 0) {
+			changed()
+			return
+		}
+		keys := oldValue.MapKeys()
+		for _, k := range newValue.MapKeys() {
+			if !oldValue.MapIndex(k).IsValid() {
+				keys = append(keys, k)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool { return lessValue(keys[i], keys[j]) })
+		for _, k := range keys {
+			diff(changes, joinPath(path, keyString(k)), oldValue.MapIndex(k), newValue.MapIndex(k))
+		}
+	default:
+		if !reflect.DeepEqual(interfaceOf(oldValue), interfaceOf(newValue)) {
+			changed()
+		}
+	}
+}
+
+func interfaceOf(v reflect.Value) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func keyString(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+	str := reflect.New(reflect.TypeOf("")).Elem()
+	if ok, _ := set(str, k, Option{WeaklyTyped: true, TextMarshaling: true}, nil); ok {
+		return str.String()
+	}
+	return fmt.Sprint(k.Interface())
+}
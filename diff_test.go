@@ -0,0 +1,121 @@
+package copier_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type DiffAddress struct {
+	City string
+	Zip  string
+}
+
+type DiffUser struct {
+	Name     string
+	Age      int
+	Password string `copier:"-"`
+	Address  *DiffAddress
+	Tags     []string
+	Labels   map[string]string
+}
+
+func TestDiff(t *testing.T) {
+	old := DiffUser{
+		Name:     "jinzhu",
+		Age:      18,
+		Password: "old",
+		Address:  &DiffAddress{City: "Shanghai", Zip: "200000"},
+		Tags:     []string{"a", "b"},
+		Labels:   map[string]string{"env": "dev", "team": "core"},
+	}
+	updated := DiffUser{
+		Name:     "jinzhu",
+		Age:      19,
+		Password: "new",
+		Address:  &DiffAddress{City: "Hangzhou", Zip: "200000"},
+		Tags:     []string{"a"},
+		Labels:   map[string]string{"env": "prod", "team": "core", "tier": "1"},
+	}
+
+	changes, err := copier.Diff(&old, &updated, copier.Option{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []copier.Change{
+		{Path: "Age", Old: 18, New: 19},
+		{Path: "Address.City", Old: "Shanghai", New: "Hangzhou"},
+		{Path: "Tags.1", Old: "b", New: nil},
+		{Path: "Labels.env", Old: "dev", New: "prod"},
+		{Path: "Labels.tier", Old: nil, New: "1"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %+v, got %+v", expected, changes)
+	}
+	if old.Address.City != "Shanghai" || old.Age != 18 {
+		t.Errorf("Diff should not modify its arguments: %+v", old)
+	}
+}
+
+func TestDiffDifferentTypes(t *testing.T) {
+	type UserRow struct {
+		ID       int
+		FullName string
+		Age      int
+	}
+
+	type UserUpdate struct {
+		Name string
+		Age  int
+	}
+
+	row := UserRow{ID: 1, FullName: "jinzhu", Age: 18}
+	opt := copier.Option{
+		IgnoreEmpty: true,
+		FieldNameMapping: []copier.FieldNameMapping{
+			{SrcType: UserUpdate{}, DstType: UserRow{}, Mapping: map[string]string{"Name": "FullName"}},
+		},
+	}
+
+	changes, err := copier.Diff(&row, &UserUpdate{Name: "Jinzhu"}, opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []copier.Change{{Path: "FullName", Old: "jinzhu", New: "Jinzhu"}}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %+v, got %+v", expected, changes)
+	}
+}
+
+func TestDiffNoChanges(t *testing.T) {
+	user := DiffUser{Name: "jinzhu", Tags: []string{"a"}}
+	changes, err := copier.Diff(user, user, copier.Option{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+type DiffTaggedUser struct {
+	Nickname    string
+	DisplayName string `copier:"from=Nickname"`
+	Email       string `copier:"transform=lower"`
+}
+
+func TestDiffTaggedDestination(t *testing.T) {
+	user := DiffTaggedUser{Nickname: "n", DisplayName: "Custom", Email: "A@X.COM"}
+	changes, err := copier.Diff(&user, &user, copier.Option{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []copier.Change{{Path: "DisplayName", Old: "Custom", New: "n"}, {Path: "Email", Old: "A@X.COM", New: "a@x.com"}}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %+v, got %+v", expected, changes)
+	}
+}
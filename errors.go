@@ -1,6 +1,11 @@
 package copier
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
 
 var (
 	ErrInvalidCopyDestination        = errors.New("copy destination must be non-nil and addressable")
@@ -8,4 +13,75 @@ var (
 	ErrMapKeyNotMatch                = errors.New("map's key type doesn't match")
 	ErrNotSupported                  = errors.New("not supported")
 	ErrFieldNameTagStartNotUpperCase = errors.New("copier field name tag must be start upper case")
+	ErrCoerceFailed                  = errors.New("cannot coerce value")
+	ErrSliceKeyNotFound              = errors.New("slice key field not found")
+	ErrDuplicateKey                  = errors.New("duplicate key")
+	ErrArrayLength                   = errors.New("array length doesn't match")
+	ErrUnknownKey                    = errors.New("unknown key")
+	ErrFieldNotAllowed               = errors.New("field not allowed")
+	ErrInvalidPatch                  = errors.New("invalid patch")
+	ErrPatchTestFailed               = errors.New("patch test failed")
+	ErrUnknownTransform              = errors.New("unknown transform")
+	ErrInvalidProfile                = errors.New("invalid profile")
 )
+
+// ConversionError is returned when a value can't be converted to the type of its destination,
+// e.g. when sql.Scanner, driver.Valuer or strconv fail.
+type ConversionError struct {
+	// Field is the dotted path of the destination field, empty when copying a top-level value
+	Field string
+	Src   reflect.Type
+	Dst   reflect.Type
+	Err   error
+}
+
+func (e *ConversionError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("cannot convert %v to %v: %v", e.Src, e.Dst, e.Err)
+	}
+	return fmt.Sprintf("cannot convert field %s from %v to %v: %v", e.Field, e.Src, e.Dst, e.Err)
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// DeniedError is returned when fields aren't copied as they aren't allowed in secure mode, once the allowed ones are copied.
+type DeniedError struct {
+	// Fields are the dotted paths of the denied destination fields, e.g. `Profile.Verified` or `Items.0.Secret`
+	Fields []string
+}
+
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrFieldNotAllowed, strings.Join(e.Fields, ", "))
+}
+
+func (e *DeniedError) Unwrap() error {
+	return ErrFieldNotAllowed
+}
+
+// nestedDenied appends the fields denied by the copy of the nested field name to denied, or returns err if it isn't a denial.
+func nestedDenied(denied []string, name string, err error) ([]string, error) {
+	deniedErr, ok := err.(*DeniedError)
+	if !ok {
+		return denied, err
+	}
+	for _, field := range deniedErr.Fields {
+		denied = appendOnce(denied, name+"."+field)
+	}
+	return denied, nil
+}
+
+// withField prefixes the field path of a ConversionError with name.
+func withField(err error, name string) error {
+	convErr, ok := err.(*ConversionError)
+	if !ok {
+		return err
+	}
+	if convErr.Field == "" {
+		convErr.Field = name
+	} else {
+		convErr.Field = name + "." + convErr.Field
+	}
+	return err
+}
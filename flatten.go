@@ -0,0 +1,253 @@
+package copier
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Flatten copies a struct into a flat map with keys joined by opt.KeySeparator, e.g. `db.pool.max_idle` or `hosts.0`.
+// Keys are named from the copier tag name or the field name with opt.KeyName, fields tagged with `copier:"-"` are skipped.
+func Flatten(fromValue interface{}, opt Option) (map[string]string, error) {
+	opt.WeaklyTyped, opt.TextMarshaling = true, true
+	result := map[string]string{}
+	if err := flatten(result, "", reflect.ValueOf(fromValue), opt, opt.converters()); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Unflatten copies a flat map with keys joined by opt.KeySeparator into a struct, parsing values into the field types.
+func Unflatten(toValue interface{}, fromValue map[string]string, opt Option) error {
+	to := reflect.ValueOf(toValue)
+	if to.Kind() != reflect.Ptr || to.IsNil() {
+		return ErrInvalidCopyDestination
+	}
+
+	opt.WeaklyTyped, opt.TextMarshaling = true, true
+	converters := opt.converters()
+
+	keys := make([]string, 0, len(fromValue))
+	for key := range fromValue {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		parts := strings.Split(key, opt.keySeparator())
+		if err := unflatten(to.Elem(), parts, reflect.ValueOf(fromValue[key]), opt, converters); err != nil {
+			if _, ok := err.(*ConversionError); ok {
+				return withField(err, key)
+			}
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func (opt Option) keySeparator() string {
+	if opt.KeySeparator == "" {
+		return "."
+	}
+	return opt.KeySeparator
+}
+
+// keyName returns the key of a struct field, or false if the field is ignored.
+func (opt Option) keyName(field reflect.StructField) (string, bool) {
+	name := field.Name
+	if tags := field.Tag.Get("copier"); tags != "" {
+		flg, tagName, _ := parseTags(tags)
+		if flg&tagIgnore != 0 {
+			return "", false
+		}
+		if tagName != "" {
+			name = tagName
+		}
+	}
+	if opt.KeyName != nil {
+		return opt.KeyName(name), true
+	}
+	return SnakeCase(name), true
+}
+
+func flatten(result map[string]string, prefix string, from reflect.Value, opt Option, converters map[converterPair]TypeConverter) error {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + opt.keySeparator() + key
+	}
+
+	if from.Kind() == reflect.Interface || from.Kind() == reflect.Ptr {
+		if from.IsNil() {
+			return nil
+		}
+		return flatten(result, prefix, from.Elem(), opt, converters)
+	}
+
+	if isScalar(from, converters) {
+		str := reflect.New(reflect.TypeOf("")).Elem()
+		isSet, err := set(str, from, opt, converters)
+		if err != nil {
+			return withField(err, prefix)
+		}
+		if !isSet {
+			str.SetString(fmt.Sprint(from.Interface()))
+		}
+		result[prefix] = str.String()
+		return nil
+	}
+
+	switch from.Kind() {
+	case reflect.Struct:
+		for i := 0; i < from.NumField(); i++ {
+			field := from.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name, ok := opt.keyName(field)
+			if !ok {
+				continue
+			}
+			if field.Anonymous && indirect(from.Field(i)).Kind() == reflect.Struct {
+				name = ""
+			}
+			key := prefix
+			if name != "" {
+				key = join(name)
+			}
+			if err := flatten(result, key, from.Field(i), opt, converters); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < from.Len(); i++ {
+			if err := flatten(result, join(strconv.Itoa(i)), from.Index(i), opt, converters); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for _, k := range from.MapKeys() {
+			key := reflect.New(reflect.TypeOf("")).Elem()
+			if isSet, err := set(key, k, opt, converters); err != nil {
+				return err
+			} else if !isSet {
+				key.SetString(fmt.Sprint(k.Interface()))
+			}
+			if err := flatten(result, join(key.String()), from.MapIndex(k), opt, converters); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isScalar reports whether v is flattened into a single value rather than walked into.
+func isScalar(v reflect.Value, converters map[converterPair]TypeConverter) bool {
+	if _, ok := converters[converterPair{SrcType: v.Type(), DstType: reflect.TypeOf("")}]; ok {
+		return true
+	}
+	if _, ok := textMarshaler(v); ok {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Array:
+		return false
+	case reflect.Slice:
+		return v.Type().Elem().Kind() == reflect.Uint8
+	}
+	return true
+}
+
+func unflatten(to reflect.Value, parts []string, value reflect.Value, opt Option, converters map[converterPair]TypeConverter) error {
+	if len(parts) == 0 {
+		return copyValue(to, value, opt, converters)
+	}
+
+	if to.Kind() == reflect.Ptr {
+		if to.IsNil() {
+			to.Set(reflect.New(to.Type().Elem()))
+		}
+		return unflatten(to.Elem(), parts, value, opt, converters)
+	}
+
+	switch to.Kind() {
+	case reflect.Struct:
+		if field, ok := opt.fieldByKey(to, parts[0]); ok {
+			return unflatten(field, parts[1:], value, opt, converters)
+		}
+		// embedded structs share the key prefix of their parent
+		for i := 0; i < to.NumField(); i++ {
+			if f := to.Type().Field(i); f.Anonymous && f.PkgPath == "" {
+				if err := unflatten(to.Field(i), parts, value, opt, converters); !errors.Is(err, ErrUnknownKey) {
+					return err
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		index, err := strconv.Atoi(parts[0])
+		if err != nil || index < 0 {
+			break
+		}
+		if index >= to.Len() {
+			if to.Kind() == reflect.Array {
+				return fmt.Errorf("%w: index %d out of %v", ErrArrayLength, index, to.Type())
+			}
+			to.Set(reflect.AppendSlice(to, reflect.MakeSlice(to.Type(), index+1-to.Len(), index+1-to.Len())))
+		}
+		return unflatten(to.Index(index), parts[1:], value, opt, converters)
+	case reflect.Map:
+		if to.IsNil() {
+			to.Set(reflect.MakeMap(to.Type()))
+		}
+		key := reflect.New(to.Type().Key()).Elem()
+		if err := copyValue(key, reflect.ValueOf(parts[0]), opt, converters); err != nil {
+			return err
+		}
+		elem := reflect.New(to.Type().Elem()).Elem()
+		if existing := to.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := unflatten(elem, parts[1:], value, opt, converters); err != nil {
+			return err
+		}
+		to.SetMapIndex(key, elem)
+		return nil
+	}
+	return ErrUnknownKey
+}
+
+// fieldByKey returns the field of the struct v named key.
+func (opt Option) fieldByKey(v reflect.Value, key string) (reflect.Value, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" || field.Anonymous {
+			continue
+		}
+		if name, ok := opt.keyName(field); ok && (name == key || (!opt.CaseSensitive && strings.EqualFold(name, key))) {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// SnakeCase converts a field name into snake case, e.g. `MaxIdle` into `max_idle` and `HTTPServer` into `http_server`.
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
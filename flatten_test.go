@@ -0,0 +1,153 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/copier"
+)
+
+type FlatPool struct {
+	MaxIdle int
+	Timeout time.Duration
+}
+
+type FlatDB struct {
+	Host     string
+	Pool     *FlatPool
+	Replicas []string
+	Password string `copier:"-"`
+}
+
+type FlatConfig struct {
+	DB        FlatDB
+	Debug     bool
+	Labels    map[string]string
+	StartedAt time.Time
+}
+
+func TestFlatten(t *testing.T) {
+	config := FlatConfig{
+		DB: FlatDB{
+			Host:     "localhost",
+			Pool:     &FlatPool{MaxIdle: 5, Timeout: 30 * time.Second},
+			Replicas: []string{"r1", "r2"},
+			Password: "secret",
+		},
+		Debug:     true,
+		Labels:    map[string]string{"env": "prod"},
+		StartedAt: time.Date(2021, 3, 5, 1, 30, 0, 0, time.UTC),
+	}
+
+	flat, err := copier.Flatten(&config, copier.Option{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"db.host":          "localhost",
+		"db.pool.max_idle": "5",
+		"db.pool.timeout":  "30s",
+		"db.replicas.0":    "r1",
+		"db.replicas.1":    "r2",
+		"debug":            "true",
+		"labels.env":       "prod",
+		"started_at":       "2021-03-05T01:30:00Z",
+	}
+	if !reflect.DeepEqual(flat, expected) {
+		t.Errorf("expected %v, got %v", expected, flat)
+	}
+}
+
+func TestFlattenSeparatorAndKeyName(t *testing.T) {
+	flat, err := copier.Flatten(FlatPool{MaxIdle: 5}, copier.Option{
+		KeySeparator: "__",
+		KeyName:      func(name string) string { return name },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flat["MaxIdle"] != "5" || flat["Timeout"] != "0s" {
+		t.Errorf("unexpected keys: %v", flat)
+	}
+
+	type Env struct {
+		DB FlatPool
+	}
+
+	var env Env
+	err = copier.Unflatten(&env, map[string]string{"DB__MaxIdle": "7"}, copier.Option{
+		KeySeparator: "__",
+		KeyName:      func(name string) string { return name },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if env.DB.MaxIdle != 7 {
+		t.Errorf("unexpected result: %+v", env)
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	flat := map[string]string{
+		"db.host":          "localhost",
+		"db.pool.max_idle": "5",
+		"db.pool.timeout":  "30s",
+		"db.replicas.1":    "r2",
+		"db.replicas.0":    "r1",
+		"debug":            "true",
+		"labels.env":       "prod",
+		"started_at":       "2021-03-05T01:30:00Z",
+	}
+
+	var config FlatConfig
+	if err := copier.Unflatten(&config, flat, copier.Option{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := FlatConfig{
+		DB: FlatDB{
+			Host:     "localhost",
+			Pool:     &FlatPool{MaxIdle: 5, Timeout: 30 * time.Second},
+			Replicas: []string{"r1", "r2"},
+		},
+		Debug:     true,
+		Labels:    map[string]string{"env": "prod"},
+		StartedAt: time.Date(2021, 3, 5, 1, 30, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %+v, got %+v", expected, config)
+	}
+}
+
+func TestUnflattenErrors(t *testing.T) {
+	var config FlatConfig
+	err := copier.Unflatten(&config, map[string]string{"db.pool.max_idle": "many"}, copier.Option{})
+
+	var convErr *copier.ConversionError
+	if !errors.As(err, &convErr) || convErr.Field != "db.pool.max_idle" {
+		t.Errorf("expected ConversionError for db.pool.max_idle, got %v", err)
+	}
+
+	err = copier.Unflatten(&config, map[string]string{"db.password": "secret"}, copier.Option{})
+	if !errors.Is(err, copier.ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"MaxIdle":    "max_idle",
+		"ID":         "id",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"Port8080":   "port8080",
+		"V2Config":   "v2_config",
+	} {
+		if got := copier.SnakeCase(name); got != expected {
+			t.Errorf("SnakeCase(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package copier
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Precedence defines which source supplies a destination field copied from several sources with CopyMany
+type Precedence uint8
+
+const (
+	// PrecedenceLastWins copies the sources in order, later sources overwriting the fields copied from earlier ones, the default
+	PrecedenceLastWins Precedence = iota
+	// PrecedenceFirstNonZero copies each field from the first source holding a non-zero value for it
+	PrecedenceFirstNonZero
+)
+
+// CopyMany copies several sources into toValue according to opt.Precedence, each source matched like with CopyWithOption.
+// Nil sources are skipped. It returns the index of the source which supplied each destination path, with paths like the
+// ones of CopyWithResult. With opt.Atomic, toValue is left untouched when copying any source fails.
+func CopyMany(toValue interface{}, opt Option, fromValues ...interface{}) (supplied map[string]int, err error) {
+	to := indirect(reflect.ValueOf(toValue))
+	if !to.CanAddr() {
+		return nil, ErrInvalidCopyDestination
+	}
+
+	// restore the destination in place when copying any source fails in atomic mode
+	if opt.Atomic {
+		opt.Atomic = false
+		saved := saveState(to, map[uintptr]bool{})
+		defer func() {
+			if r := recover(); r != nil {
+				saved.restore()
+				panic(r)
+			}
+			if err != nil {
+				saved.restore()
+			}
+		}()
+	}
+
+	order := make([]int, len(fromValues))
+	for i := range order {
+		order[i] = i
+	}
+	if opt.Precedence == PrecedenceFirstNonZero {
+		// copy the sources in reverse order without their zero values, so the first non-zero value is copied last,
+		// and deeply so nested structs are merged field by field
+		opt.IgnoreEmpty, opt.DeepCopy = true, true
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	supplied = map[string]int{}
+	for _, i := range order {
+		if from := indirect(reflect.ValueOf(fromValues[i])); !from.IsValid() {
+			continue
+		}
+
+		var result Result
+		opt.result = &result
+		if err := copier(to.Addr().Interface(), fromValues[i], opt); err != nil {
+			return supplied, fmt.Errorf("source %d: %w", i, err)
+		}
+		for _, path := range result.Written {
+			supplied[path] = i
+		}
+	}
+
+	return supplied, nil
+}
@@ -0,0 +1,113 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type ManyProfile struct {
+	Bio    string
+	Avatar string
+}
+
+type ManyResponse struct {
+	ID       int
+	Name     string
+	Email    string
+	Theme    string
+	Beta     bool
+	Profile  ManyProfile
+	Settings map[string]string
+}
+
+type ManyUserRecord struct {
+	ID    int
+	Name  string
+	Email string
+}
+
+type ManyProfileRecord struct {
+	Name    string
+	Profile ManyProfile
+}
+
+type ManyPreferences struct {
+	Theme    string
+	Profile  ManyProfile
+	Settings map[string]string
+}
+
+type ManyFlags struct {
+	Beta bool
+}
+
+func TestCopyMany(t *testing.T) {
+	user := ManyUserRecord{ID: 1, Name: "jinzhu", Email: "jinzhu@example.com"}
+	profile := ManyProfileRecord{Name: "Jinzhu", Profile: ManyProfile{Bio: "gopher"}}
+	preferences := ManyPreferences{Theme: "dark", Profile: ManyProfile{Avatar: "avatar.png"}, Settings: map[string]string{"lang": "en"}}
+	var flags *ManyFlags
+
+	var response ManyResponse
+	supplied, err := copier.CopyMany(&response, copier.Option{}, &user, &profile, &preferences, flags)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := ManyResponse{
+		ID:       1,
+		Name:     "Jinzhu",
+		Email:    "jinzhu@example.com",
+		Theme:    "dark",
+		Profile:  ManyProfile{Avatar: "avatar.png"},
+		Settings: map[string]string{"lang": "en"},
+	}
+	if !reflect.DeepEqual(response, expected) {
+		t.Errorf("expected %+v, got %+v", expected, response)
+	}
+
+	expectedSupplied := map[string]int{"ID": 0, "Name": 1, "Email": 0, "Theme": 2, "Profile": 2, "Settings": 2}
+	if !reflect.DeepEqual(supplied, expectedSupplied) {
+		t.Errorf("expected %v, got %v", expectedSupplied, supplied)
+	}
+}
+
+func TestCopyManyFirstNonZero(t *testing.T) {
+	user := ManyUserRecord{ID: 1, Name: "jinzhu"}
+	profile := ManyProfileRecord{Name: "Jinzhu", Profile: ManyProfile{Bio: "gopher"}}
+	preferences := ManyPreferences{Theme: "dark", Profile: ManyProfile{Bio: "ignored", Avatar: "avatar.png"}}
+	flags := ManyFlags{Beta: true}
+
+	var response ManyResponse
+	supplied, err := copier.CopyMany(&response, copier.Option{Precedence: copier.PrecedenceFirstNonZero}, user, profile, preferences, flags)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := ManyResponse{ID: 1, Name: "jinzhu", Theme: "dark", Beta: true, Profile: ManyProfile{Bio: "gopher", Avatar: "avatar.png"}}
+	if !reflect.DeepEqual(response, expected) {
+		t.Errorf("expected %+v, got %+v", expected, response)
+	}
+
+	expectedSupplied := map[string]int{"ID": 0, "Name": 0, "Theme": 2, "Beta": 3, "Profile.Bio": 1, "Profile.Avatar": 2}
+	if !reflect.DeepEqual(supplied, expectedSupplied) {
+		t.Errorf("expected %v, got %v", expectedSupplied, supplied)
+	}
+}
+
+func TestCopyManyAtomic(t *testing.T) {
+	type Source struct {
+		ID string
+	}
+
+	response := ManyResponse{Name: "old"}
+	_, err := copier.CopyMany(&response, copier.Option{WeaklyTyped: true, Atomic: true}, &ManyUserRecord{Name: "new"}, &Source{ID: "x"})
+	if !errors.Is(err, copier.ErrCoerceFailed) {
+		t.Fatalf("expected ErrCoerceFailed, got %v", err)
+	}
+	if response.Name != "old" {
+		t.Errorf("expected the destination to be untouched, got %+v", response)
+	}
+}
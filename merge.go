@@ -0,0 +1,208 @@
+package copier
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Conflict is a path changed differently by ours and theirs in Merge3, with Diff-like paths
+type Conflict struct {
+	Path   string
+	Base   interface{}
+	Ours   interface{}
+	Theirs interface{}
+}
+
+// Merge3 copies the three-way merge of ours and theirs, two versions of base, into toValue and returns the conflicts.
+//
+// base, ours and theirs of another type than toValue are copied into its type with opt first, so they're matched like with
+// CopyWithOption, versions of the same type are cloned as is.
+// Changes made by one side only are applied, changes made by both sides are merged field by field in structs, key by key
+// in maps and by key in slices of elements with a `copier:"key"` field or a key in opt.SliceKeys. Other values changed
+// differently by both sides are conflicts and are set from ours.
+func Merge3(toValue interface{}, base, ours, theirs interface{}, opt Option) ([]Conflict, error) {
+	to := indirect(reflect.ValueOf(toValue))
+	if !to.CanAddr() {
+		return nil, ErrInvalidCopyDestination
+	}
+
+	opt.DeepCopy = true
+	versions := make([]reflect.Value, 3)
+	for i, from := range []interface{}{base, ours, theirs} {
+		versions[i] = reflect.New(to.Type())
+		if v := indirect(reflect.ValueOf(from)); v.IsValid() && v.Type() == to.Type() {
+			versions[i].Elem().Set(cloneValue(v, map[uintptr]reflect.Value{}))
+		} else if err := CopyWithOption(versions[i].Interface(), from, opt); err != nil {
+			return nil, err
+		}
+		versions[i] = versions[i].Elem()
+	}
+
+	var conflicts []Conflict
+	merged := merge3(&conflicts, "", versions[0], versions[1], versions[2], opt)
+	setMerged(to, merged)
+	return conflicts, nil
+}
+
+// setMerged sets to to merged, keeping the fields of to tagged with `copier:"-"` as copying would.
+func setMerged(to, merged reflect.Value) {
+	if to.Kind() != reflect.Struct {
+		to.Set(merged)
+		return
+	}
+	for i := 0; i < to.NumField(); i++ {
+		if !to.Field(i).CanSet() {
+			continue
+		}
+		if tags := to.Type().Field(i).Tag.Get("copier"); tags != "" {
+			if flg, _, _ := parseTags(tags); flg&tagIgnore != 0 {
+				continue
+			}
+		}
+		setMerged(to.Field(i), merged.Field(i))
+	}
+}
+
+// merge3 returns the three-way merge of base, ours and theirs, invalid values are missing map keys or slice elements.
+func merge3(conflicts *[]Conflict, path string, base, ours, theirs reflect.Value, opt Option) reflect.Value {
+	switch {
+	case equalValues(ours, theirs), equalValues(base, theirs):
+		return ours
+	case equalValues(base, ours):
+		return theirs
+	}
+
+	if base.IsValid() && ours.IsValid() && theirs.IsValid() {
+		if merged, ok := mergeChanges(conflicts, path, base, ours, theirs, opt); ok {
+			return merged
+		}
+	}
+	*conflicts = append(*conflicts, Conflict{Path: path, Base: interfaceOf(base), Ours: interfaceOf(ours), Theirs: interfaceOf(theirs)})
+	return ours
+}
+
+// mergeChanges merges the values changed by both ours and theirs, or returns false if they can't be merged.
+func mergeChanges(conflicts *[]Conflict, path string, base, ours, theirs reflect.Value, opt Option) (reflect.Value, bool) {
+	switch ours.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if base.IsNil() || ours.IsNil() || theirs.IsNil() {
+			return ours, false
+		}
+		if ours.Kind() == reflect.Interface && (ours.Elem().Type() != base.Elem().Type() || ours.Elem().Type() != theirs.Elem().Type()) {
+			return ours, false
+		}
+		elem := merge3(conflicts, path, base.Elem(), ours.Elem(), theirs.Elem(), opt)
+		if ours.Kind() == reflect.Interface {
+			merged := reflect.New(ours.Type()).Elem()
+			merged.Set(elem)
+			return merged, true
+		}
+		merged := reflect.New(ours.Type().Elem())
+		merged.Elem().Set(elem)
+		return merged, true
+	case reflect.Struct:
+		if len(deepFields(ours.Type())) == 0 {
+			return ours, false
+		}
+		merged := reflect.New(ours.Type()).Elem()
+		merged.Set(ours)
+		for i := 0; i < ours.NumField(); i++ {
+			field := ours.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if tags := field.Tag.Get("copier"); tags != "" {
+				if flg, _, _ := parseTags(tags); flg&tagIgnore != 0 {
+					continue
+				}
+			}
+			fieldPath := joinPath(path, field.Name)
+			if field.Anonymous && indirectKind(field.Type) == reflect.Struct {
+				fieldPath = path
+			}
+			merged.Field(i).Set(merge3(conflicts, fieldPath, base.Field(i), ours.Field(i), theirs.Field(i), opt))
+		}
+		return merged, true
+	case reflect.Map:
+		keys := ours.MapKeys()
+		for _, m := range []reflect.Value{base, theirs} {
+			for _, k := range m.MapKeys() {
+				if !ours.MapIndex(k).IsValid() && !containsKey(keys, k) {
+					keys = append(keys, k)
+				}
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool { return lessValue(keys[i], keys[j]) })
+
+		merged := reflect.MakeMapWithSize(ours.Type(), len(keys))
+		for _, k := range keys {
+			if elem := merge3(conflicts, joinPath(path, keyString(k)), base.MapIndex(k), ours.MapIndex(k), theirs.MapIndex(k), opt); elem.IsValid() {
+				merged.SetMapIndex(k, elem)
+			}
+		}
+		return merged, true
+	case reflect.Slice:
+		keyField, ok := opt.sliceKey(ours.Type().Elem())
+		if !ok {
+			return ours, false
+		}
+
+		keyOf := func(v reflect.Value) reflect.Value {
+			if v = indirect(v); !v.IsValid() {
+				return v
+			}
+			if key := fieldByName(v, keyField, opt.CaseSensitive); key.IsValid() && key.Type().Comparable() {
+				return key
+			}
+			return reflect.Value{}
+		}
+		index := func(s reflect.Value) map[interface{}]reflect.Value {
+			elems := map[interface{}]reflect.Value{}
+			for i := 0; i < s.Len(); i++ {
+				if key := keyOf(s.Index(i)); key.IsValid() {
+					elems[key.Interface()] = s.Index(i)
+				}
+			}
+			return elems
+		}
+
+		// keep the order of ours, followed by the elements added by theirs
+		baseElems, oursElems, theirsElems := index(base), index(ours), index(theirs)
+		var keys []reflect.Value
+		for _, s := range []reflect.Value{ours, theirs} {
+			for i := 0; i < s.Len(); i++ {
+				if key := keyOf(s.Index(i)); !key.IsValid() {
+					return ours, false
+				} else if !containsKey(keys, key) {
+					keys = append(keys, key)
+				}
+			}
+		}
+
+		merged := reflect.MakeSlice(ours.Type(), 0, len(keys))
+		for _, key := range keys {
+			k := key.Interface()
+			if elem := merge3(conflicts, joinPath(path, keyString(key)), baseElems[k], oursElems[k], theirsElems[k], opt); elem.IsValid() {
+				merged = reflect.Append(merged, elem)
+			}
+		}
+		return merged, true
+	}
+	return ours, false
+}
+
+func equalValues(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+func containsKey(keys []reflect.Value, key reflect.Value) bool {
+	for _, k := range keys {
+		if k.Interface() == key.Interface() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package copier_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type MergeItem struct {
+	SKU      string `copier:"key"`
+	Quantity int
+	Note     string
+}
+
+type MergeAddress struct {
+	City string
+	Zip  string
+}
+
+type MergeOrder struct {
+	Status   string
+	Address  *MergeAddress
+	Items    []MergeItem
+	Tags     []string
+	Metadata map[string]string
+	Internal string `copier:"-"`
+}
+
+func newMergeOrder() MergeOrder {
+	return MergeOrder{
+		Status:   "pending",
+		Address:  &MergeAddress{City: "Shanghai", Zip: "200000"},
+		Items:    []MergeItem{{SKU: "a", Quantity: 1}, {SKU: "b", Quantity: 2}},
+		Tags:     []string{"new"},
+		Metadata: map[string]string{"source": "web", "channel": "ads"},
+	}
+}
+
+func TestMerge3(t *testing.T) {
+	base := newMergeOrder()
+
+	ours := newMergeOrder()
+	ours.Status = "paid"
+	ours.Address.City = "Hangzhou"
+	ours.Items[0].Quantity = 3
+	ours.Items = append(ours.Items, MergeItem{SKU: "c", Quantity: 1})
+	ours.Metadata["source"] = "app"
+
+	theirs := newMergeOrder()
+	theirs.Address.Zip = "310000"
+	theirs.Items[0].Note = "gift"
+	theirs.Items = theirs.Items[:1]
+	theirs.Metadata["coupon"] = "x"
+	delete(theirs.Metadata, "channel")
+
+	merged := MergeOrder{Internal: "kept"}
+	conflicts, err := copier.Merge3(&merged, base, ours, theirs, copier.Option{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %+v", conflicts)
+	}
+
+	expected := MergeOrder{
+		Status:   "paid",
+		Address:  &MergeAddress{City: "Hangzhou", Zip: "310000"},
+		Items:    []MergeItem{{SKU: "a", Quantity: 3, Note: "gift"}, {SKU: "c", Quantity: 1}},
+		Tags:     []string{"new"},
+		Metadata: map[string]string{"source": "app", "coupon": "x"},
+		Internal: "kept",
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("expected %+v, got %+v", expected, merged)
+	}
+}
+
+func TestMerge3Conflicts(t *testing.T) {
+	base := newMergeOrder()
+
+	ours := newMergeOrder()
+	ours.Status = "paid"
+	ours.Tags = []string{"new", "vip"}
+	ours.Items[1].Quantity = 5
+	ours.Metadata["source"] = "app"
+
+	theirs := newMergeOrder()
+	theirs.Status = "cancelled"
+	theirs.Tags = []string{"new", "fraud"}
+	theirs.Items = theirs.Items[:1]
+	theirs.Metadata["source"] = "app"
+
+	var merged MergeOrder
+	conflicts, err := copier.Merge3(&merged, &base, &ours, &theirs, copier.Option{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedConflicts := []copier.Conflict{
+		{Path: "Status", Base: "pending", Ours: "paid", Theirs: "cancelled"},
+		{Path: "Items.b", Base: MergeItem{SKU: "b", Quantity: 2}, Ours: MergeItem{SKU: "b", Quantity: 5}, Theirs: nil},
+		{Path: "Tags", Base: []string{"new"}, Ours: []string{"new", "vip"}, Theirs: []string{"new", "fraud"}},
+	}
+	if !reflect.DeepEqual(conflicts, expectedConflicts) {
+		t.Errorf("expected %+v, got %+v", expectedConflicts, conflicts)
+	}
+	if !reflect.DeepEqual(merged, ours) {
+		t.Errorf("expected conflicts to be set from ours %+v, got %+v", ours, merged)
+	}
+}
+
+func TestMerge3TaggedDestination(t *testing.T) {
+	type User struct {
+		Nickname    string
+		DisplayName string `copier:"from=Nickname"`
+		Email       string `copier:"transform=lower"`
+	}
+
+	base := User{Nickname: "n", DisplayName: "n", Email: "A@X.COM"}
+	ours := base
+	ours.DisplayName = "Custom"
+	theirs := base
+	theirs.Nickname = "m"
+
+	var merged User
+	conflicts, err := copier.Merge3(&merged, base, ours, theirs, copier.Option{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %+v", conflicts)
+	}
+	if expected := (User{Nickname: "m", DisplayName: "Custom", Email: "A@X.COM"}); merged != expected {
+		t.Errorf("expected %+v, got %+v", expected, merged)
+	}
+}
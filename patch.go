@@ -0,0 +1,794 @@
+package copier
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PatchOperation is an operation of a JSON Patch (RFC 6902), e.g. `{"op": "replace", "path": "/hosts/0/port", "value": 8080}`
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON omits the value of the operations which don't take one.
+func (op PatchOperation) MarshalJSON() ([]byte, error) {
+	type operation PatchOperation
+	if op.Op == "remove" || op.Op == "move" || op.Op == "copy" {
+		return json.Marshal(struct {
+			operation
+			Value interface{} `json:"value,omitempty"`
+		}{operation: operation(op)})
+	}
+	return json.Marshal(operation(op))
+}
+
+// ApplyMergePatch applies a JSON Merge Patch (RFC 7396) decoded into a map onto toValue: objects are merged field by field
+// and key by key, null values zero fields and delete map keys, other values replace the destination.
+//
+// Keys are matched with the json tag name, the copier tag name or the field name, fields tagged with `copier:"-"` or `json:"-"`
+// are unknown keys. Values are copied with the converters of opt, secure mode applies to the patched fields and fields tagged
+// with `copier:"must"` can't be removed. The patch is applied entirely or not at all.
+func ApplyMergePatch(toValue interface{}, patch map[string]interface{}, opt Option) error {
+	return applyPatch(toValue, opt, func(to reflect.Value, opt Option, converters map[converterPair]TypeConverter) error {
+		return mergePatch(to, patch, "", opt, converters)
+	})
+}
+
+// ApplyJSONPatch applies the operations of a JSON Patch (RFC 6902) onto toValue, with JSON pointers resolved like the keys of
+// ApplyMergePatch. The patch is applied entirely or not at all, a failing `test` operation returns ErrPatchTestFailed.
+func ApplyJSONPatch(toValue interface{}, patch []PatchOperation, opt Option) error {
+	return applyPatch(toValue, opt, func(to reflect.Value, opt Option, converters map[converterPair]TypeConverter) error {
+		for _, op := range patch {
+			if err := applyOperation(to, op, opt, converters); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CreateMergePatch returns the JSON Merge Patch turning `a` into `b`, `a` and `b` may be of different types as `b` is
+// copied into a copy of `a` with opt first. Keys are named like the ones of ApplyMergePatch.
+func CreateMergePatch(a, b interface{}, opt Option) (map[string]interface{}, error) {
+	base, copied, err := copyInto(a, b, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	oldDoc, ok := toJSON(base).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w merge patch of %v", ErrNotSupported, base.Type().Elem())
+	}
+	newDoc, _ := toJSON(copied).(map[string]interface{})
+	return mergeDiff(oldDoc, newDoc), nil
+}
+
+// CreateJSONPatch returns the JSON Patch turning `a` into `b`, `a` and `b` may be of different types as `b` is
+// copied into a copy of `a` with opt first. Paths are named like the ones of ApplyJSONPatch.
+func CreateJSONPatch(a, b interface{}, opt Option) ([]PatchOperation, error) {
+	base, copied, err := copyInto(a, b, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []PatchOperation
+	jsonPatchDiff(&ops, "", toJSON(base), toJSON(copied))
+	return ops, nil
+}
+
+// applyPatch applies a patch onto toValue in place, which is restored when the patch fails like with Option.Atomic.
+func applyPatch(toValue interface{}, opt Option, apply func(reflect.Value, Option, map[converterPair]TypeConverter) error) (err error) {
+	to := reflect.ValueOf(toValue)
+	if to.Kind() != reflect.Ptr || to.IsNil() {
+		return ErrInvalidCopyDestination
+	}
+
+	opt.WeaklyTyped, opt.TextMarshaling = true, true
+	saved := saveState(to.Elem(), map[uintptr]bool{})
+	defer func() {
+		if r := recover(); r != nil {
+			saved.restore()
+			panic(r)
+		}
+	}()
+	if err = apply(to.Elem(), opt, opt.converters()); err != nil {
+		saved.restore()
+	}
+	return err
+}
+
+func mergePatch(to reflect.Value, patch map[string]interface{}, path string, opt Option, converters map[converterPair]TypeConverter) error {
+	switch to.Kind() {
+	case reflect.Ptr:
+		if to.IsNil() {
+			to.Set(reflect.New(to.Type().Elem()))
+		}
+		return mergePatch(to.Elem(), patch, path, opt, converters)
+	case reflect.Interface:
+		var doc interface{}
+		if !to.IsNil() {
+			doc = to.Interface()
+		}
+		to.Set(reflect.ValueOf(mergeJSON(doc, patch)))
+		return nil
+	case reflect.Struct, reflect.Map:
+	default:
+		return setJSON(to, patch, path, opt, converters)
+	}
+
+	keys := make([]string, 0, len(patch))
+	for key := range patch {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if to.Kind() == reflect.Map && to.IsNil() {
+		to.Set(reflect.MakeMap(to.Type()))
+	}
+
+	for _, key := range keys {
+		value, keyPath := patch[key], joinPath(path, key)
+		object, isObject := value.(map[string]interface{})
+
+		if to.Kind() == reflect.Map {
+			mapKey, err := patchMapKey(to, key, keyPath, opt, converters)
+			if err != nil {
+				return err
+			}
+			if value == nil {
+				to.SetMapIndex(mapKey, reflect.Value{})
+				continue
+			}
+			elem := reflect.New(to.Type().Elem()).Elem()
+			if existing := to.MapIndex(mapKey); existing.IsValid() && isObject {
+				elem.Set(existing)
+				err = mergePatch(elem, object, keyPath, opt, converters)
+			} else {
+				err = setJSON(elem, value, keyPath, opt, converters)
+			}
+			if err != nil {
+				return err
+			}
+			to.SetMapIndex(mapKey, elem)
+			continue
+		}
+
+		field, structField, flg, err := patchField(to, key, keyPath, opt)
+		if err != nil {
+			return err
+		}
+		switch {
+		case value == nil:
+			err = removeField(field, structField, flg)
+		case isObject:
+			err = mergePatch(field, object, keyPath, opt, converters)
+		default:
+			err = setJSON(field, value, keyPath, opt, converters)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeJSON applies a merge patch onto a decoded JSON document without modifying it.
+func mergeJSON(doc interface{}, patch interface{}) interface{} {
+	object, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	merged := map[string]interface{}{}
+	if docObject, ok := doc.(map[string]interface{}); ok {
+		for key, value := range docObject {
+			merged[key] = value
+		}
+	}
+	for key, value := range object {
+		if value == nil {
+			delete(merged, key)
+		} else {
+			merged[key] = mergeJSON(merged[key], value)
+		}
+	}
+	return merged
+}
+
+// setJSON replaces `to` with a decoded JSON value, objects are decoded into structs and maps key by key.
+func setJSON(to reflect.Value, value interface{}, path string, opt Option, converters map[converterPair]TypeConverter) error {
+	if value == nil {
+		to.Set(reflect.Zero(to.Type()))
+		return nil
+	}
+
+	switch to.Kind() {
+	case reflect.Ptr:
+		if _, isObject := value.(map[string]interface{}); isObject || to.IsNil() {
+			to.Set(reflect.New(to.Type().Elem()))
+		}
+		return setJSON(to.Elem(), value, path, opt, converters)
+	case reflect.Struct, reflect.Map:
+		if object, ok := value.(map[string]interface{}); ok {
+			to.Set(reflect.Zero(to.Type()))
+			return mergePatch(to, object, path, opt, converters)
+		}
+	case reflect.Slice, reflect.Array:
+		if array, ok := value.([]interface{}); ok {
+			if to.Kind() == reflect.Slice {
+				to.Set(reflect.MakeSlice(to.Type(), len(array), len(array)))
+			} else if len(array) != to.Len() {
+				return fmt.Errorf("%w: %d elements into %v at %s", ErrArrayLength, len(array), to.Type(), path)
+			}
+			for i, elem := range array {
+				if err := setJSON(to.Index(i), elem, joinPath(path, strconv.Itoa(i)), opt, converters); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	if number, ok := value.(float64); ok {
+		if err := checkInteger(to, number, path, converters); err != nil {
+			return err
+		}
+	}
+	if err := copyValue(to, reflect.ValueOf(value), opt, converters); err != nil {
+		return withField(err, path)
+	}
+	return nil
+}
+
+// checkInteger checks JSON numbers, decoded as float64, fit integer fields without being truncated or wrapped,
+// unless a converter converts them.
+func checkInteger(to reflect.Value, number float64, path string, converters map[converterPair]TypeConverter) error {
+	if _, ok := converters[converterPair{SrcType: reflect.TypeOf(number), DstType: to.Type()}]; ok {
+		return nil
+	}
+
+	switch to.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if number != math.Trunc(number) || number < math.MinInt64 || number >= math.MaxInt64 || to.OverflowInt(int64(number)) {
+			return fmt.Errorf("%w: %v doesn't fit %v at %s", ErrInvalidPatch, number, to.Type(), path)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if number != math.Trunc(number) || number < 0 || number >= math.MaxUint64 || to.OverflowUint(uint64(number)) {
+			return fmt.Errorf("%w: %v doesn't fit %v at %s", ErrInvalidPatch, number, to.Type(), path)
+		}
+	}
+	return nil
+}
+
+func applyOperation(root reflect.Value, op PatchOperation, opt Option, converters map[converterPair]TypeConverter) error {
+	tokens, err := parsePointer(op.Path)
+	if err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case "add", "replace", "remove":
+		if len(tokens) == 0 {
+			if op.Op == "remove" {
+				return fmt.Errorf("%w: remove of the whole document", ErrInvalidPatch)
+			}
+			return setJSON(root, op.Value, "", opt, converters)
+		}
+		return patchAt(root, tokens, "", opt, converters, func(container reflect.Value, token, path string) error {
+			return patchContainer(container, op.Op, token, op.Value, path, opt, converters)
+		})
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return err
+		}
+		if op.Op == "move" && strings.HasPrefix(op.Path+"/", op.From+"/") && op.Path != op.From {
+			return fmt.Errorf("%w: cannot move %s into itself", ErrInvalidPatch, op.From)
+		}
+		value, err := lookupPointer(root, from, opt, converters)
+		if err != nil {
+			return err
+		}
+		doc := toJSON(value)
+		if op.Op == "move" {
+			if err := applyOperation(root, PatchOperation{Op: "remove", Path: op.From}, opt, converters); err != nil {
+				return err
+			}
+		}
+		return applyOperation(root, PatchOperation{Op: "add", Path: op.Path, Value: doc}, opt, converters)
+	case "test":
+		actual, err := lookupPointer(root, tokens, opt, converters)
+		if err != nil {
+			return err
+		}
+		if actual.Kind() == reflect.Interface {
+			actual = actual.Elem()
+		}
+		if !actual.IsValid() {
+			if op.Value != nil {
+				return fmt.Errorf("%w: %s is null", ErrPatchTestFailed, op.Path)
+			}
+			return nil
+		}
+		expected := reflect.New(actual.Type()).Elem()
+		if err := setJSON(expected, op.Value, "", opt, converters); err != nil || !reflect.DeepEqual(toJSON(actual), toJSON(expected)) {
+			return fmt.Errorf("%w: %s is %v", ErrPatchTestFailed, op.Path, toJSON(actual))
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, op.Op)
+}
+
+// patchAt walks `v` along tokens and calls fn with the container of the last token, setting back
+// the map elements and interface values it walked through.
+func patchAt(v reflect.Value, tokens []string, path string, opt Option, converters map[converterPair]TypeConverter, fn func(container reflect.Value, token, path string) error) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return fmt.Errorf("%w: %s", ErrUnknownKey, path)
+		}
+		return patchAt(v.Elem(), tokens, path, opt, converters, fn)
+	case reflect.Interface:
+		if v.IsNil() {
+			return fmt.Errorf("%w: %s", ErrUnknownKey, path)
+		}
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		if err := patchAt(elem, tokens, path, opt, converters, fn); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	token, childPath := tokens[0], joinPath(path, tokens[0])
+	if len(tokens) == 1 {
+		return fn(v, token, childPath)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		field, _, _, err := patchField(v, token, childPath, opt)
+		if err != nil {
+			return err
+		}
+		return patchAt(field, tokens[1:], childPath, opt, converters, fn)
+	case reflect.Map:
+		key, err := patchMapKey(v, token, childPath, opt, converters)
+		if err != nil {
+			return err
+		}
+		existing := v.MapIndex(key)
+		if !existing.IsValid() {
+			return fmt.Errorf("%w: %s", ErrUnknownKey, childPath)
+		}
+		elem := reflect.New(existing.Type()).Elem()
+		elem.Set(existing)
+		if err := patchAt(elem, tokens[1:], childPath, opt, converters, fn); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+		return nil
+	case reflect.Slice, reflect.Array:
+		index, err := patchIndex(v, token, childPath, false)
+		if err != nil {
+			return err
+		}
+		return patchAt(v.Index(index), tokens[1:], childPath, opt, converters, fn)
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownKey, childPath)
+}
+
+// patchContainer applies an add, replace or remove operation of the element token of container.
+func patchContainer(container reflect.Value, op, token string, value interface{}, path string, opt Option, converters map[converterPair]TypeConverter) error {
+	switch container.Kind() {
+	case reflect.Struct:
+		field, structField, flg, err := patchField(container, token, path, opt)
+		if err != nil {
+			return err
+		}
+		if op == "remove" {
+			return removeField(field, structField, flg)
+		}
+		return setJSON(field, value, path, opt, converters)
+	case reflect.Map:
+		key, err := patchMapKey(container, token, path, opt, converters)
+		if err != nil {
+			return err
+		}
+		if op != "add" && !container.MapIndex(key).IsValid() {
+			return fmt.Errorf("%w: %s", ErrUnknownKey, path)
+		}
+		if op == "remove" {
+			container.SetMapIndex(key, reflect.Value{})
+			return nil
+		}
+		if container.IsNil() {
+			container.Set(reflect.MakeMap(container.Type()))
+		}
+		elem := reflect.New(container.Type().Elem()).Elem()
+		if err := setJSON(elem, value, path, opt, converters); err != nil {
+			return err
+		}
+		container.SetMapIndex(key, elem)
+		return nil
+	case reflect.Slice:
+		index, err := patchIndex(container, token, path, op == "add")
+		if err != nil {
+			return err
+		}
+		switch op {
+		case "add":
+			elem := reflect.New(container.Type().Elem()).Elem()
+			if err := setJSON(elem, value, path, opt, converters); err != nil {
+				return err
+			}
+			slice := reflect.MakeSlice(container.Type(), 0, container.Len()+1)
+			slice = reflect.Append(reflect.AppendSlice(slice, container.Slice(0, index)), elem)
+			container.Set(reflect.AppendSlice(slice, container.Slice(index, container.Len())))
+		case "remove":
+			slice := reflect.MakeSlice(container.Type(), 0, container.Len()-1)
+			slice = reflect.AppendSlice(slice, container.Slice(0, index))
+			container.Set(reflect.AppendSlice(slice, container.Slice(index+1, container.Len())))
+		default:
+			return setJSON(container.Index(index), value, path, opt, converters)
+		}
+		return nil
+	case reflect.Array:
+		index, err := patchIndex(container, token, path, false)
+		if err != nil {
+			return err
+		}
+		if op == "remove" {
+			value = nil
+		}
+		return setJSON(container.Index(index), value, path, opt, converters)
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownKey, path)
+}
+
+// lookupPointer returns the value at tokens without modifying v.
+func lookupPointer(v reflect.Value, tokens []string, opt Option, converters map[converterPair]TypeConverter) (reflect.Value, error) {
+	var path string
+	for _, token := range tokens {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return v, fmt.Errorf("%w: %s", ErrUnknownKey, path)
+			}
+			v = v.Elem()
+		}
+
+		path = joinPath(path, token)
+		switch v.Kind() {
+		case reflect.Struct:
+			index, _, ok := patchFieldIndex(v.Type(), token, opt.CaseSensitive)
+			if !ok {
+				return v, fmt.Errorf("%w: %s", ErrUnknownKey, path)
+			}
+			for i, x := range index {
+				if i > 0 && v.Kind() == reflect.Ptr {
+					if v.IsNil() {
+						return v, fmt.Errorf("%w: %s", ErrUnknownKey, path)
+					}
+					v = v.Elem()
+				}
+				v = v.Field(x)
+			}
+		case reflect.Map:
+			key, err := patchMapKey(v, token, path, opt, converters)
+			if err != nil {
+				return v, err
+			}
+			if v = v.MapIndex(key); !v.IsValid() {
+				return v, fmt.Errorf("%w: %s", ErrUnknownKey, path)
+			}
+		case reflect.Slice, reflect.Array:
+			index, err := patchIndex(v, token, path, false)
+			if err != nil {
+				return v, err
+			}
+			v = v.Index(index)
+		default:
+			return v, fmt.Errorf("%w: %s", ErrUnknownKey, path)
+		}
+	}
+	return v, nil
+}
+
+// patchField returns the field of the struct v named key and its tag flags, allocating the nil embedded structs it's promoted from.
+func patchField(v reflect.Value, key, path string, opt Option) (_ reflect.Value, field reflect.StructField, flg uint16, err error) {
+	index, field, ok := patchFieldIndex(v.Type(), key, opt.CaseSensitive)
+	if !ok {
+		return v, field, flg, fmt.Errorf("%w: %s", ErrUnknownKey, path)
+	}
+
+	if tags := field.Tag.Get("copier"); tags != "" {
+		flg, _, _ = parseTags(tags)
+	}
+	if !opt.allowed(field.Name, flg) {
+		return v, field, flg, fmt.Errorf("%w: %s", ErrFieldNotAllowed, path)
+	}
+
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, field, flg, nil
+}
+
+// patchFieldIndex returns the index of the field of struct type t named key, preferring exact matches over case-insensitive ones.
+func patchFieldIndex(t reflect.Type, key string, caseSensitive bool) ([]int, reflect.StructField, bool) {
+	if index, field, ok := findPatchField(t, func(name string) bool { return name == key }); ok || caseSensitive {
+		return index, field, ok
+	}
+	return findPatchField(t, func(name string) bool { return strings.EqualFold(name, key) })
+}
+
+func findPatchField(t reflect.Type, match func(string) bool) ([]int, reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, ok := patchName(field)
+		if !ok {
+			continue
+		}
+		if isInlined(field, name) {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if index, promoted, ok := findPatchField(embedded, match); ok {
+				return append([]int{i}, index...), promoted, true
+			}
+			continue
+		}
+		if match(name) {
+			return []int{i}, field, true
+		}
+	}
+	return nil, reflect.StructField{}, false
+}
+
+// patchName returns the key of a field, or false if the field is ignored.
+func patchName(field reflect.StructField) (string, bool) {
+	name := field.Name
+	if tags := field.Tag.Get("copier"); tags != "" {
+		flg, tagName, _ := parseTags(tags)
+		if flg&tagIgnore != 0 {
+			return "", false
+		}
+		if tagName != "" {
+			name = tagName
+		}
+	}
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		if tag = strings.Split(tag, ",")[0]; tag == "-" {
+			return "", false
+		} else if tag != "" {
+			name = tag
+		}
+	}
+	return name, true
+}
+
+// isInlined reports whether the fields of an embedded struct are keys of its parent, as it isn't renamed by a tag.
+func isInlined(field reflect.StructField, name string) bool {
+	return field.Anonymous && name == field.Name && indirectKind(field.Type) == reflect.Struct
+}
+
+// removeField zeroes a field, fields tagged with `copier:"must"` can't be removed and return ErrInvalidPatch,
+// as patches come from untrusted input they never panic.
+func removeField(to reflect.Value, field reflect.StructField, flg uint16) error {
+	if flg&tagMust != 0 {
+		return fmt.Errorf("%w: field %s has must tag and can't be removed", ErrInvalidPatch, field.Name)
+	}
+	to.Set(reflect.Zero(to.Type()))
+	return nil
+}
+
+func patchMapKey(m reflect.Value, token, path string, opt Option, converters map[converterPair]TypeConverter) (reflect.Value, error) {
+	key := reflect.New(m.Type().Key()).Elem()
+	if err := copyValue(key, reflect.ValueOf(token), opt, converters); err != nil {
+		return key, fmt.Errorf("%w: %s: %v", ErrUnknownKey, path, err)
+	}
+	return key, nil
+}
+
+// patchIndex parses the array index token, `-` or the length are only valid when adding an element.
+func patchIndex(v reflect.Value, token, path string, adding bool) (int, error) {
+	if token == "-" && adding {
+		return v.Len(), nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > v.Len() || (index == v.Len() && !adding) || (token != "0" && token[0] == '0') {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownKey, path)
+	}
+	return index, nil
+}
+
+// parsePointer splits a JSON pointer (RFC 6901) into unescaped tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("%w: path %q doesn't start with /", ErrInvalidPatch, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = pointerUnescaper.Replace(token)
+	}
+	return tokens, nil
+}
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// joinPath appends name to the dotted path of a field, element or map key, like the paths of Diff and Result.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// toJSON returns v as a decoded JSON document, with structs as maps keyed like ApplyMergePatch and
+// encoding.TextMarshaler types as strings.
+func toJSON(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return toJSON(v.Elem())
+	}
+
+	if marshaler, ok := textMarshaler(v); ok {
+		if text, err := marshaler.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if len(deepFields(v.Type())) == 0 {
+			return v.Interface()
+		}
+		doc := map[string]interface{}{}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name, ok := patchName(field)
+			if !ok {
+				continue
+			}
+			if isInlined(field, name) {
+				if embedded, ok := toJSON(v.Field(i)).(map[string]interface{}); ok {
+					for key, value := range embedded {
+						doc[key] = value
+					}
+				}
+				continue
+			}
+			doc[name] = toJSON(v.Field(i))
+		}
+		return doc
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		doc := map[string]interface{}{}
+		for _, key := range v.MapKeys() {
+			doc[keyString(key)] = toJSON(v.MapIndex(key))
+		}
+		return doc
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8) {
+			return interfaceOf(v)
+		}
+		array := make([]interface{}, v.Len())
+		for i := range array {
+			array[i] = toJSON(v.Index(i))
+		}
+		return array
+	}
+	return interfaceOf(v)
+}
+
+// mergeDiff returns the merge patch turning the object oldDoc into newDoc.
+func mergeDiff(oldDoc, newDoc map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for key := range oldDoc {
+		if _, ok := newDoc[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	for key, newValue := range newDoc {
+		oldValue, ok := oldDoc[key]
+		oldObject, isOldObject := oldValue.(map[string]interface{})
+		newObject, isNewObject := newValue.(map[string]interface{})
+		switch {
+		case ok && isOldObject && isNewObject:
+			if nested := mergeDiff(oldObject, newObject); len(nested) > 0 {
+				patch[key] = nested
+			}
+		case !ok || !reflect.DeepEqual(oldValue, newValue):
+			patch[key] = newValue
+		}
+	}
+	return patch
+}
+
+// jsonPatchDiff appends the operations turning the document oldDoc at pointer into newDoc.
+func jsonPatchDiff(ops *[]PatchOperation, pointer string, oldDoc, newDoc interface{}) {
+	switch oldValue := oldDoc.(type) {
+	case map[string]interface{}:
+		if newValue, ok := newDoc.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(oldValue)+len(newValue))
+			for key := range oldValue {
+				keys = append(keys, key)
+			}
+			for key := range newValue {
+				if _, ok := oldValue[key]; !ok {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				keyPointer := pointer + "/" + pointerEscaper.Replace(key)
+				oldElem, inOld := oldValue[key]
+				newElem, inNew := newValue[key]
+				switch {
+				case !inNew:
+					*ops = append(*ops, PatchOperation{Op: "remove", Path: keyPointer})
+				case !inOld:
+					*ops = append(*ops, PatchOperation{Op: "add", Path: keyPointer, Value: newElem})
+				default:
+					jsonPatchDiff(ops, keyPointer, oldElem, newElem)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if newValue, ok := newDoc.([]interface{}); ok {
+			for i := 0; i < len(oldValue) && i < len(newValue); i++ {
+				jsonPatchDiff(ops, pointer+"/"+strconv.Itoa(i), oldValue[i], newValue[i])
+			}
+			for i := len(oldValue); i < len(newValue); i++ {
+				*ops = append(*ops, PatchOperation{Op: "add", Path: pointer + "/" + strconv.Itoa(i), Value: newValue[i]})
+			}
+			// remove from the end so the indexes of the remaining elements don't shift
+			for i := len(oldValue) - 1; i >= len(newValue); i-- {
+				*ops = append(*ops, PatchOperation{Op: "remove", Path: pointer + "/" + strconv.Itoa(i)})
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(oldDoc, newDoc) {
+		*ops = append(*ops, PatchOperation{Op: "replace", Path: pointer, Value: newDoc})
+	}
+}
@@ -0,0 +1,313 @@
+package copier_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/copier"
+)
+
+type PatchHost struct {
+	Name string `json:"name"`
+	Port int    `json:"port"`
+}
+
+type PatchConfig struct {
+	Name    string            `json:"name"`
+	Timeout time.Duration     `json:"timeout"`
+	Hosts   []PatchHost       `json:"hosts"`
+	Labels  map[string]string `json:"labels"`
+	Owner   *PatchHost        `json:"owner"`
+	Secret  string            `copier:"-"`
+	Version int               `copier:"must,nopanic"`
+}
+
+func newPatchConfig() PatchConfig {
+	return PatchConfig{
+		Name:    "api",
+		Timeout: time.Second,
+		Hosts:   []PatchHost{{Name: "a", Port: 80}, {Name: "b", Port: 81}},
+		Labels:  map[string]string{"env": "dev", "team": "core"},
+		Owner:   &PatchHost{Name: "jinzhu"},
+		Secret:  "secret",
+		Version: 1,
+	}
+}
+
+func decodeJSON(t *testing.T, data string, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	var patch map[string]interface{}
+	decodeJSON(t, `{"timeout": "5s", "hosts": [{"name": "c", "port": 8080}], "labels": {"env": "prod", "team": null}, "owner": {"port": 22}}`, &patch)
+
+	config := newPatchConfig()
+	if err := copier.ApplyMergePatch(&config, patch, copier.Option{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := newPatchConfig()
+	expected.Timeout = 5 * time.Second
+	expected.Hosts = []PatchHost{{Name: "c", Port: 8080}}
+	expected.Labels = map[string]string{"env": "prod"}
+	expected.Owner = &PatchHost{Name: "jinzhu", Port: 22}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %+v, got %+v", expected, config)
+	}
+
+	decodeJSON(t, `{"owner": null, "name": null}`, &patch)
+	if err := copier.ApplyMergePatch(&config, patch, copier.Option{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Owner != nil || config.Name != "" {
+		t.Errorf("expected null values to zero fields, got %+v", config)
+	}
+}
+
+func TestApplyMergePatchErrors(t *testing.T) {
+	config := newPatchConfig()
+
+	err := copier.ApplyMergePatch(&config, map[string]interface{}{"name": "new", "Secret": "leaked"}, copier.Option{})
+	if !errors.Is(err, copier.ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+
+	err = copier.ApplyMergePatch(&config, map[string]interface{}{"name": "new", "version": nil}, copier.Option{})
+	if !errors.Is(err, copier.ErrInvalidPatch) {
+		t.Errorf("expected ErrInvalidPatch removing a must field, got %v", err)
+	}
+
+	err = copier.ApplyMergePatch(&config, map[string]interface{}{"name": "new", "timeout": "soon"}, copier.Option{})
+	var convErr *copier.ConversionError
+	if !errors.As(err, &convErr) || convErr.Field != "timeout" {
+		t.Errorf("expected ConversionError for timeout, got %v", err)
+	}
+
+	err = copier.ApplyMergePatch(&config, map[string]interface{}{"name": "new"}, copier.Option{Allow: []string{"Timeout"}})
+	if !errors.Is(err, copier.ErrFieldNotAllowed) {
+		t.Errorf("expected ErrFieldNotAllowed, got %v", err)
+	}
+
+	if !reflect.DeepEqual(config, newPatchConfig()) {
+		t.Errorf("failed patches should leave the destination untouched, got %+v", config)
+	}
+}
+
+func TestApplyPatchMustNoPanic(t *testing.T) {
+	type Account struct {
+		Name string `copier:"must"`
+		Note string
+	}
+
+	account := Account{Name: "jinzhu", Note: "note"}
+	if err := copier.ApplyMergePatch(&account, map[string]interface{}{"Name": nil}, copier.Option{}); !errors.Is(err, copier.ErrInvalidPatch) {
+		t.Errorf("expected ErrInvalidPatch, got %v", err)
+	}
+	if err := copier.ApplyJSONPatch(&account, []copier.PatchOperation{{Op: "remove", Path: "/Name"}}, copier.Option{}); !errors.Is(err, copier.ErrInvalidPatch) {
+		t.Errorf("expected ErrInvalidPatch, got %v", err)
+	}
+	if account.Name != "jinzhu" {
+		t.Errorf("expected the destination to be untouched, got %+v", account)
+	}
+}
+
+func TestApplyPatchKeepsPointers(t *testing.T) {
+	type Settings struct {
+		Theme string
+		Lang  string
+	}
+	type User struct {
+		Name     string
+		Settings *Settings
+		Labels   map[string]string
+	}
+
+	settings, labels := &Settings{Theme: "a", Lang: "en"}, map[string]string{"env": "dev"}
+	user := User{Name: "jinzhu", Settings: settings, Labels: labels}
+	if err := copier.ApplyMergePatch(&user, map[string]interface{}{"Settings": map[string]interface{}{"Theme": "b"}, "Labels": map[string]interface{}{"env": "prod"}}, copier.Option{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.Settings != settings || settings.Theme != "b" || settings.Lang != "en" || labels["env"] != "prod" {
+		t.Errorf("expected the patch to be applied in place, got %+v %+v", user, settings)
+	}
+
+	// failed patches restore the destination in place
+	err := copier.ApplyJSONPatch(&user, []copier.PatchOperation{
+		{Op: "replace", Path: "/Settings/Theme", Value: "c"},
+		{Op: "test", Path: "/Name", Value: "other"},
+	}, copier.Option{})
+	if !errors.Is(err, copier.ErrPatchTestFailed) {
+		t.Errorf("expected ErrPatchTestFailed, got %v", err)
+	}
+	if user.Settings != settings || settings.Theme != "b" {
+		t.Errorf("expected the destination to be restored, got %+v %+v", user, settings)
+	}
+}
+
+func TestApplyPatchIntegers(t *testing.T) {
+	type Counter struct {
+		Count int8
+		Total uint
+	}
+
+	for _, patch := range []map[string]interface{}{
+		{"Count": 1.5},
+		{"Count": float64(300)},
+		{"Total": float64(-1)},
+		{"Total": 1e30},
+	} {
+		counter := Counter{Count: 1, Total: 2}
+		if err := copier.ApplyMergePatch(&counter, patch, copier.Option{}); !errors.Is(err, copier.ErrInvalidPatch) {
+			t.Errorf("expected ErrInvalidPatch for %v, got %v", patch, err)
+		}
+		if counter != (Counter{Count: 1, Total: 2}) {
+			t.Errorf("expected the destination to be untouched, got %+v", counter)
+		}
+	}
+
+	var counter Counter
+	if err := copier.ApplyMergePatch(&counter, map[string]interface{}{"Count": float64(-128), "Total": float64(7)}, copier.Option{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if counter != (Counter{Count: -128, Total: 7}) {
+		t.Errorf("unexpected result: %+v", counter)
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	var patch []copier.PatchOperation
+	decodeJSON(t, `[
+		{"op": "test", "path": "/hosts/0/port", "value": 80},
+		{"op": "replace", "path": "/hosts/0/port", "value": 8080},
+		{"op": "add", "path": "/hosts/1", "value": {"name": "c", "port": 82}},
+		{"op": "add", "path": "/hosts/-", "value": {"name": "d"}},
+		{"op": "remove", "path": "/hosts/2"},
+		{"op": "add", "path": "/labels/tier", "value": "1"},
+		{"op": "remove", "path": "/labels/team"},
+		{"op": "copy", "from": "/owner/name", "path": "/name"},
+		{"op": "move", "from": "/labels/env", "path": "/labels/stage"}
+	]`, &patch)
+
+	config := newPatchConfig()
+	if err := copier.ApplyJSONPatch(&config, patch, copier.Option{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := newPatchConfig()
+	expected.Name = "jinzhu"
+	expected.Hosts = []PatchHost{{Name: "a", Port: 8080}, {Name: "c", Port: 82}, {Name: "d"}}
+	expected.Labels = map[string]string{"tier": "1", "stage": "dev"}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %+v, got %+v", expected, config)
+	}
+}
+
+func TestApplyJSONPatchErrors(t *testing.T) {
+	config := newPatchConfig()
+
+	tests := []struct {
+		ops []copier.PatchOperation
+		err error
+	}{
+		{[]copier.PatchOperation{{Op: "replace", Path: "/name", Value: "new"}, {Op: "test", Path: "/hosts/0/name", Value: "b"}}, copier.ErrPatchTestFailed},
+		{[]copier.PatchOperation{{Op: "replace", Path: "/name", Value: "new"}, {Op: "remove", Path: "/labels/missing"}}, copier.ErrUnknownKey},
+		{[]copier.PatchOperation{{Op: "replace", Path: "/hosts/5/port", Value: 1}}, copier.ErrUnknownKey},
+		{[]copier.PatchOperation{{Op: "replace", Path: "/secret", Value: "leaked"}}, copier.ErrUnknownKey},
+		{[]copier.PatchOperation{{Op: "rename", Path: "/name"}}, copier.ErrInvalidPatch},
+		{[]copier.PatchOperation{{Op: "move", From: "/owner", Path: "/owner/name"}}, copier.ErrInvalidPatch},
+	}
+
+	for _, tt := range tests {
+		if err := copier.ApplyJSONPatch(&config, tt.ops, copier.Option{}); !errors.Is(err, tt.err) {
+			t.Errorf("%+v: expected %v, got %v", tt.ops, tt.err, err)
+		}
+	}
+
+	if !reflect.DeepEqual(config, newPatchConfig()) {
+		t.Errorf("failed patches should leave the destination untouched, got %+v", config)
+	}
+}
+
+func TestCreatePatch(t *testing.T) {
+	old := newPatchConfig()
+	updated := newPatchConfig()
+	updated.Timeout = 2 * time.Second
+	updated.Hosts = updated.Hosts[:1]
+	updated.Labels = map[string]string{"env": "prod", "team": "core", "tier": "1"}
+	updated.Owner = nil
+
+	mergePatch, err := copier.CreateMergePatch(&old, &updated, copier.Option{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedMerge := map[string]interface{}{
+		"timeout": 2 * time.Second,
+		"hosts":   []interface{}{map[string]interface{}{"name": "a", "port": 80}},
+		"labels":  map[string]interface{}{"env": "prod", "tier": "1"},
+		"owner":   nil,
+	}
+	if !reflect.DeepEqual(mergePatch, expectedMerge) {
+		t.Errorf("expected %+v, got %+v", expectedMerge, mergePatch)
+	}
+
+	jsonPatch, err := copier.CreateJSONPatch(&old, &updated, copier.Option{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedOps := []copier.PatchOperation{
+		{Op: "remove", Path: "/hosts/1"},
+		{Op: "replace", Path: "/labels/env", Value: "prod"},
+		{Op: "add", Path: "/labels/tier", Value: "1"},
+		{Op: "replace", Path: "/owner", Value: nil},
+		{Op: "replace", Path: "/timeout", Value: 2 * time.Second},
+	}
+	if !reflect.DeepEqual(jsonPatch, expectedOps) {
+		t.Errorf("expected %+v, got %+v", expectedOps, jsonPatch)
+	}
+
+	// the generated patches round-trip through JSON
+	for _, apply := range []func(*PatchConfig) error{
+		func(config *PatchConfig) error {
+			var patch map[string]interface{}
+			data, _ := json.Marshal(mergePatch)
+			decodeJSON(t, string(data), &patch)
+			return copier.ApplyMergePatch(config, patch, copier.Option{})
+		},
+		func(config *PatchConfig) error {
+			var patch []copier.PatchOperation
+			data, _ := json.Marshal(jsonPatch)
+			decodeJSON(t, string(data), &patch)
+			return copier.ApplyJSONPatch(config, patch, copier.Option{})
+		},
+	} {
+		config := newPatchConfig()
+		if err := apply(&config); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(config, updated) {
+			t.Errorf("expected %+v, got %+v", updated, config)
+		}
+	}
+}
+
+func TestApplyPatchToMap(t *testing.T) {
+	doc := map[string]interface{}{"name": "api", "hosts": []interface{}{"a"}}
+	if err := copier.ApplyJSONPatch(&doc, []copier.PatchOperation{{Op: "add", Path: "/hosts/-", Value: "b"}}, copier.Option{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := copier.ApplyMergePatch(&doc, map[string]interface{}{"name": nil, "port": 80}, copier.Option{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]interface{}{"hosts": []interface{}{"a", "b"}, "port": 80}
+	if !reflect.DeepEqual(doc, expected) {
+		t.Errorf("expected %+v, got %+v", expected, doc)
+	}
+}
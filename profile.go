@@ -0,0 +1,296 @@
+package copier
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Profile declares how a source type is copied into a destination type, like copier tags maintained outside the code,
+// e.g. loaded from a JSON file with LoadProfiles. Profiles are set in Option.Profiles and apply on top of the tags.
+type Profile struct {
+	SrcType interface{}
+	DstType interface{}
+	// Fields maps destination field names to the way they're copied
+	Fields map[string]FieldProfile
+}
+
+// FieldProfile declares how a destination field is copied, each setting working like the equivalent copier tag.
+type FieldProfile struct {
+	// From renames the source field or method, or lists candidates separated by `|` like `copier:"from=A|B"`
+	From string `json:"from,omitempty"`
+	// Ignore excludes the field like `copier:"-"`
+	Ignore bool `json:"ignore,omitempty"`
+	// Default is the value of the field when it wasn't copied like `copier:"default=value"`
+	Default *string `json:"default,omitempty"`
+	// Transform lists transforms separated by `|` like `copier:"transform=trim|lower"`
+	Transform string `json:"transform,omitempty"`
+	// Converter names a converter registered with RegisterConverter, which only converts the value of this field
+	Converter string `json:"converter,omitempty"`
+}
+
+// profileJSON is a profile as written in JSON, naming its types
+type profileJSON struct {
+	Source      string                  `json:"source"`
+	Destination string                  `json:"destination"`
+	Fields      map[string]FieldProfile `json:"fields"`
+}
+
+var (
+	convertersLock  sync.RWMutex
+	namedConverters = map[string]TypeConverter{}
+)
+
+// RegisterConverter registers a converter usable by name in profiles, replacing any converter of the same name.
+func RegisterConverter(name string, converter TypeConverter) {
+	convertersLock.Lock()
+	defer convertersLock.Unlock()
+	namedConverters[name] = converter
+}
+
+func namedConverter(name string) (TypeConverter, bool) {
+	convertersLock.RLock()
+	defer convertersLock.RUnlock()
+	converter, ok := namedConverters[name]
+	return converter, ok
+}
+
+// LoadProfiles reads a JSON array of profiles, e.g.
+//
+//	[{"source": "PartnerOrder", "destination": "Order", "fields": {
+//		"ID": {"from": "Ref"},
+//		"Customer": {"from": "Nickname|FullName", "transform": "trim"},
+//		"Notes": {"ignore": true},
+//		"Status": {"default": "pending"},
+//		"Total": {"from": "Amount", "converter": "cents"}
+//	}}]
+//
+// Types are named after values of types, e.g. `Order` or `models.Order` for models.Order{}. Each profile is validated
+// with Profile.Validate. YAML files aren't supported so as not to add a dependency, convert them to JSON beforehand.
+func LoadProfiles(r io.Reader, types ...interface{}) ([]Profile, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var profilesJSON []profileJSON
+	if err := decoder.Decode(&profilesJSON); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProfile, err)
+	}
+
+	profiles := make([]Profile, 0, len(profilesJSON))
+	for i, p := range profilesJSON {
+		srcType, err := profileType(p.Source, types)
+		if err != nil {
+			return nil, fmt.Errorf("%w: profile %d: source: %v", ErrInvalidProfile, i, err)
+		}
+		dstType, err := profileType(p.Destination, types)
+		if err != nil {
+			return nil, fmt.Errorf("%w: profile %d: destination: %v", ErrInvalidProfile, i, err)
+		}
+
+		profile := Profile{
+			SrcType: reflect.Zero(srcType).Interface(),
+			DstType: reflect.Zero(dstType).Interface(),
+			Fields:  p.Fields,
+		}
+		if err := profile.Validate(); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// profileType returns the type of types named name.
+func profileType(name string, types []interface{}) (reflect.Type, error) {
+	var found reflect.Type
+	for _, v := range types {
+		t, _ := indirectType(reflect.TypeOf(v))
+		if t.Name() != name && t.String() != name {
+			continue
+		}
+		if found != nil && found != t {
+			return nil, fmt.Errorf("ambiguous type %q", name)
+		}
+		found = t
+	}
+	if found == nil {
+		return nil, fmt.Errorf("unknown type %q", name)
+	}
+	return found, nil
+}
+
+// Validate checks the profile against its types: destination fields, source fields or methods, transforms and
+// converters must exist, converters must convert between the types of the fields and defaults must parse into them.
+func (p Profile) Validate() error {
+	srcType, _ := indirectType(reflect.TypeOf(p.SrcType))
+	dstType, _ := indirectType(reflect.TypeOf(p.DstType))
+	if srcType.Kind() != reflect.Struct || dstType.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %v to %v: profiles copy structs", ErrInvalidProfile, srcType, dstType)
+	}
+
+	names := make([]string, 0, len(p.Fields))
+	for name := range p.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := p.validateField(name, p.Fields[name], srcType, dstType); err != nil {
+			return fmt.Errorf("%w: %v to %v: field %s: %v", ErrInvalidProfile, srcType, dstType, name, err)
+		}
+	}
+	return nil
+}
+
+func (p Profile) validateField(name string, field FieldProfile, srcType, dstType reflect.Type) error {
+	dstField, ok := profileField(dstType, name)
+	if !ok {
+		return fmt.Errorf("unknown destination field")
+	}
+	if field.Ignore {
+		if field != (FieldProfile{Ignore: true}) {
+			return fmt.Errorf("ignored field has other settings")
+		}
+		return nil
+	}
+
+	// types of the candidate source fields or methods
+	var srcTypes []reflect.Type
+	if field.From != "" {
+		for _, from := range strings.Split(field.From, "|") {
+			t, ok := profileSource(srcType, from)
+			if !ok {
+				return fmt.Errorf("unknown source field or method %q", from)
+			}
+			srcTypes = append(srcTypes, t)
+		}
+	} else if t, ok := profileSource(srcType, name); ok {
+		srcTypes = append(srcTypes, t)
+	}
+
+	if field.Transform != "" {
+		for _, transform := range strings.Split(field.Transform, "|") {
+			transformsLock.RLock()
+			_, ok := transforms[transform]
+			transformsLock.RUnlock()
+			if !ok {
+				return fmt.Errorf("%v %q", ErrUnknownTransform, transform)
+			}
+		}
+	}
+
+	if field.Converter != "" {
+		converter, ok := namedConverter(field.Converter)
+		if !ok {
+			return fmt.Errorf("unknown converter %q", field.Converter)
+		}
+		if t := reflect.TypeOf(converter.DstType); t != dstField.Type {
+			return fmt.Errorf("converter %q converts into %v instead of %v", field.Converter, t, dstField.Type)
+		}
+		for _, t := range srcTypes {
+			if t != reflect.TypeOf(converter.SrcType) {
+				return fmt.Errorf("converter %q converts from %v instead of %v", field.Converter, reflect.TypeOf(converter.SrcType), t)
+			}
+		}
+	}
+
+	if field.Default != nil {
+		if err := setDefault(reflect.New(dstField.Type).Elem(), *field.Default, Option{}, nil); err != nil {
+			return fmt.Errorf("default: %v", err)
+		}
+	}
+	return nil
+}
+
+// profileField returns the exported field of the struct type t named name, including promoted fields.
+func profileField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for _, field := range deepFields(t) {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// profileSource returns the type of the source field or method of the struct type t named name.
+func profileSource(t reflect.Type, name string) (reflect.Type, bool) {
+	if field, ok := profileField(t, name); ok {
+		return field.Type, true
+	}
+	if method, ok := reflect.PointerTo(t).MethodByName(name); ok && method.Type.NumIn() == 1 && method.Type.NumOut() == 1 {
+		return method.Type.Out(0), true
+	}
+	return nil, false
+}
+
+func (opt Option) profiles() map[converterPair]Profile {
+	var profiles = map[converterPair]Profile{}
+
+	for _, p := range opt.Profiles {
+		srcType, _ := indirectType(reflect.TypeOf(p.SrcType))
+		dstType, _ := indirectType(reflect.TypeOf(p.DstType))
+		profiles[converterPair{SrcType: srcType, DstType: dstType}] = p
+	}
+
+	return profiles
+}
+
+// hasDefaults reports whether the profile sets default values.
+func (p Profile) hasDefaults() bool {
+	for _, field := range p.Fields {
+		if field.Default != nil && !field.Ignore {
+			return true
+		}
+	}
+	return false
+}
+
+// apply sets the flags of the destination fields of the profile, on top of the ones of their tags.
+func (p Profile) apply(flgs flags) error {
+	for name, field := range p.Fields {
+		if field.Ignore {
+			flgs.BitFlags[name] |= tagIgnore
+			delete(flgs.Sources, name)
+			continue
+		}
+		if field.From != "" {
+			flgs.BitFlags[name] |= tagFrom
+			flgs.Sources[name] = strings.Split(field.From, "|")
+		}
+		if field.Transform != "" {
+			flgs.BitFlags[name] |= tagTransform
+			flgs.Transforms[name] = strings.Split(field.Transform, "|")
+		}
+		if field.Default != nil {
+			flgs.BitFlags[name] |= tagDefault
+			flgs.Defaults[name] = *field.Default
+		}
+		if field.Converter != "" {
+			converter, ok := namedConverter(field.Converter)
+			if !ok {
+				return fmt.Errorf("%w: field %s: unknown converter %q", ErrInvalidProfile, name, field.Converter)
+			}
+			flgs.Converters[name] = converter
+		}
+	}
+	return nil
+}
+
+// fieldConverters returns converters including the converter of the destination field name set by a profile, if any.
+func (flgs flags) fieldConverters(name string, converters map[converterPair]TypeConverter) map[converterPair]TypeConverter {
+	converter, ok := flgs.Converters[name]
+	if !ok {
+		return converters
+	}
+
+	fieldConverters := make(map[converterPair]TypeConverter, len(converters)+1)
+	for pair, c := range converters {
+		fieldConverters[pair] = c
+	}
+	fieldConverters[converterPair{SrcType: reflect.TypeOf(converter.SrcType), DstType: reflect.TypeOf(converter.DstType)}] = converter
+	return fieldConverters
+}
@@ -0,0 +1,112 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type ProfilePartnerOrder struct {
+	Ref      string
+	Nickname string
+	FullName string
+	Amount   float64
+	Notes    string
+	Status   string
+}
+
+func (o ProfilePartnerOrder) Contact() string {
+	return " Jinzhu@Example.com "
+}
+
+type ProfileOrder struct {
+	ID       string
+	Customer string
+	Email    string
+	Total    int64
+	Notes    string
+	Status   string
+}
+
+const orderProfiles = `[{
+	"source": "ProfilePartnerOrder",
+	"destination": "copier_test.ProfileOrder",
+	"fields": {
+		"ID": {"from": "Ref"},
+		"Customer": {"from": "Nickname|FullName", "transform": "trim"},
+		"Email": {"from": "Contact", "transform": "trim|lower"},
+		"Total": {"from": "Amount", "converter": "cents"},
+		"Notes": {"ignore": true},
+		"Status": {"default": "pending"}
+	}
+}]`
+
+func init() {
+	copier.RegisterConverter("cents", copier.TypeConverter{
+		SrcType: copier.Float64,
+		DstType: int64(0),
+		Fn: func(src interface{}) (interface{}, error) {
+			return int64(src.(float64)*100 + 0.5), nil
+		},
+	})
+}
+
+func TestLoadProfiles(t *testing.T) {
+	profiles, err := copier.LoadProfiles(strings.NewReader(orderProfiles), ProfilePartnerOrder{}, &ProfileOrder{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	order := ProfileOrder{Notes: "internal"}
+	partnerOrder := ProfilePartnerOrder{Ref: "A-1", FullName: " Jinzhu ", Amount: 12.34, Notes: "leaked"}
+	if err := copier.CopyWithOption(&order, &partnerOrder, copier.Option{Profiles: profiles, Defaults: copier.DefaultIfZero}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := ProfileOrder{ID: "A-1", Customer: "Jinzhu", Email: "jinzhu@example.com", Total: 1234, Notes: "internal", Status: "pending"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %+v, got %+v", expected, order)
+	}
+
+	// profiles only apply to their types
+	var copied ProfilePartnerOrder
+	if err := copier.CopyWithOption(&copied, &partnerOrder, copier.Option{Profiles: profiles}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(copied, partnerOrder) {
+		t.Errorf("expected %+v, got %+v", partnerOrder, copied)
+	}
+}
+
+func TestLoadProfilesErrors(t *testing.T) {
+	tests := []struct {
+		fields string
+		err    string
+	}{
+		{`{"Totl": {"from": "Amount"}}`, "field Totl: unknown destination field"},
+		{`{"Total": {"from": "Amont"}}`, `field Total: unknown source field or method "Amont"`},
+		{`{"Total": {"form": "Amount"}}`, `unknown field "form"`},
+		{`{"ID": {"transform": "shout"}}`, `field ID: unknown transform "shout"`},
+		{`{"Total": {"converter": "dollars"}}`, `field Total: unknown converter "dollars"`},
+		{`{"Total": {"from": "Ref", "converter": "cents"}}`, `field Total: converter "cents" converts from float64 instead of string`},
+		{`{"ID": {"from": "Amount", "converter": "cents"}}`, `field ID: converter "cents" converts into int64 instead of string`},
+		{`{"Total": {"default": "many"}}`, "field Total: default:"},
+		{`{"Notes": {"ignore": true, "default": "none"}}`, "field Notes: ignored field has other settings"},
+	}
+
+	for _, tt := range tests {
+		data := `[{"source": "ProfilePartnerOrder", "destination": "ProfileOrder", "fields": ` + tt.fields + `}]`
+		_, err := copier.LoadProfiles(strings.NewReader(data), ProfilePartnerOrder{}, ProfileOrder{})
+		if !errors.Is(err, copier.ErrInvalidProfile) || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: expected an ErrInvalidProfile error containing %q, got %v", tt.fields, tt.err, err)
+		}
+	}
+
+	_, err := copier.LoadProfiles(strings.NewReader(`[{"source": "PartnerOrder", "destination": "ProfileOrder"}]`), ProfilePartnerOrder{}, ProfileOrder{})
+	if !errors.Is(err, copier.ErrInvalidProfile) || !strings.Contains(err.Error(), `profile 0: source: unknown type "PartnerOrder"`) {
+		t.Errorf("expected an unknown type error, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package copier
+
+import (
+	"reflect"
+	"strings"
+)
+
+// SkipReason explains why a destination field wasn't copied
+type SkipReason string
+
+const (
+	// SkipIgnored denotes a field tagged with `copier:"-"`
+	SkipIgnored SkipReason = "ignored"
+	// SkipEmpty denotes a field whose source value was ignored by IgnoreEmpty, IgnoreNil or `copier:"omitnil"`
+	SkipEmpty SkipReason = "empty"
+	// SkipNoMatch denotes a field without any matching source field or method
+	SkipNoMatch SkipReason = "no match"
+	// SkipUnsupported denotes a field whose type can't be copied from the source, or which can't be set
+	SkipUnsupported SkipReason = "unsupported type"
+	// SkipNotAllowed denotes a field which isn't allowed in secure mode
+	SkipNotAllowed SkipReason = "not allowed"
+	// SkipKept denotes a field kept by the Overwrite policy or `copier:"keep"`
+	SkipKept SkipReason = "kept"
+)
+
+// Skip is a destination path which wasn't copied
+type Skip struct {
+	Path   string
+	Reason SkipReason
+}
+
+// Result records what CopyWithResult did, with paths like the ones of Diff
+type Result struct {
+	// Written lists the destination paths which were copied to
+	Written []string
+	// Skipped lists the destination paths which weren't copied to and why
+	Skipped []Skip
+	// Changed lists the destination paths whose value changed
+	Changed []string
+}
+
+// CopyWithResult copies like CopyWithOption, and returns the paths it wrote, skipped and changed.
+func CopyWithResult(toValue interface{}, fromValue interface{}, opt Option) (result Result, err error) {
+	before, err := snapshot(toValue)
+	if err != nil {
+		return result, err
+	}
+
+	opt.result = &result
+	err = copier(toValue, fromValue, opt)
+
+	after, snapshotErr := snapshot(toValue)
+	if snapshotErr != nil {
+		return result, snapshotErr
+	}
+	var changes []Change
+	diff(&changes, "", before.Elem(), after.Elem())
+	for _, change := range changes {
+		result.Changed = append(result.Changed, change.Path)
+	}
+	return result, err
+}
+
+// snapshot returns a pointer to a clone of v.
+func snapshot(v interface{}) (reflect.Value, error) {
+	value := indirect(reflect.ValueOf(v))
+	if !value.IsValid() {
+		return reflect.Value{}, ErrInvalidCopyDestination
+	}
+	copied := reflect.New(value.Type())
+	copied.Elem().Set(cloneValue(value, map[uintptr]reflect.Value{}))
+	return copied, nil
+}
+
+func (r *Result) has(path string) bool {
+	for _, written := range r.Written {
+		if written == path || strings.HasPrefix(written, path+".") {
+			return true
+		}
+	}
+	for _, skipped := range r.Skipped {
+		if skipped.Path == path || strings.HasPrefix(skipped.Path, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// at returns the options for copying the field or element name of the current path.
+func (opt Option) at(name string) Option {
+	opt.path = opt.fieldPath(name)
+	return opt
+}
+
+func (opt Option) fieldPath(name string) string {
+	if name == "" {
+		return opt.path
+	}
+	return joinPath(opt.path, name)
+}
+
+func (opt Option) written(name string) {
+	if opt.result != nil {
+		opt.result.Written = append(opt.result.Written, opt.fieldPath(name))
+	}
+}
+
+func (opt Option) skipped(name string, reason SkipReason) {
+	if opt.result != nil {
+		opt.result.Skipped = append(opt.result.Skipped, Skip{Path: opt.fieldPath(name), Reason: reason})
+	}
+}
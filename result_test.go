@@ -0,0 +1,109 @@
+package copier_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jinzhu/copier"
+)
+
+type ResultAddress struct {
+	City string
+	Zip  string
+}
+
+type ResultUser struct {
+	Name     string
+	Age      int
+	Role     string `copier:"-"`
+	Address  ResultAddress
+	Nickname string
+	Email    string
+	Labels   map[string]string
+}
+
+type ResultUserInput struct {
+	Name    string
+	Age     int
+	Role    string
+	Address struct {
+		City string
+	}
+	Nickname string
+	Labels   map[string]string
+}
+
+func TestCopyWithResult(t *testing.T) {
+	user := ResultUser{Name: "jinzhu", Age: 18, Address: ResultAddress{City: "Shanghai", Zip: "200000"}, Labels: map[string]string{"env": "dev"}}
+	input := ResultUserInput{Name: "jinzhu", Age: 19, Role: "admin", Labels: map[string]string{"env": "prod"}}
+	input.Address.City = "Hangzhou"
+
+	result, err := copier.CopyWithResult(&user, &input, copier.Option{IgnoreEmpty: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedWritten := []string{"Name", "Age", "Address.City", "Labels"}
+	if !reflect.DeepEqual(result.Written, expectedWritten) {
+		t.Errorf("expected written %v, got %v", expectedWritten, result.Written)
+	}
+
+	expectedSkipped := []copier.Skip{
+		{Path: "Role", Reason: copier.SkipIgnored},
+		{Path: "Address.Zip", Reason: copier.SkipNoMatch},
+		{Path: "Nickname", Reason: copier.SkipEmpty},
+		{Path: "Email", Reason: copier.SkipNoMatch},
+	}
+	if !reflect.DeepEqual(result.Skipped, expectedSkipped) {
+		t.Errorf("expected skipped %v, got %v", expectedSkipped, result.Skipped)
+	}
+
+	expectedChanged := []string{"Age", "Address.City", "Labels.env"}
+	if !reflect.DeepEqual(result.Changed, expectedChanged) {
+		t.Errorf("expected changed %v, got %v", expectedChanged, result.Changed)
+	}
+}
+
+func TestCopyWithResultSlices(t *testing.T) {
+	type Item struct {
+		Name  string
+		Price int `copier:"keep"`
+	}
+
+	type ItemInput struct {
+		Name  string
+		Price int
+	}
+
+	items := []Item{{Name: "a", Price: 1}}
+	result, err := copier.CopyWithResult(&items, []ItemInput{{Name: "b", Price: 2}, {Name: "c", Price: 3}}, copier.Option{SliceStrategy: copier.SliceMergeIndex})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedSkipped := []copier.Skip{{Path: "0.Price", Reason: copier.SkipKept}}
+	if !reflect.DeepEqual(result.Skipped, expectedSkipped) {
+		t.Errorf("expected skipped %v, got %v", expectedSkipped, result.Skipped)
+	}
+	expectedChanged := []string{"0.Name", "1"}
+	if !reflect.DeepEqual(result.Changed, expectedChanged) {
+		t.Errorf("expected changed %v, got %v", expectedChanged, result.Changed)
+	}
+}
+
+func TestCopyWithResultTaggedDestination(t *testing.T) {
+	type User struct {
+		Nickname    string
+		DisplayName string `copier:"from=Nickname"`
+		Email       string `copier:"transform=lower"`
+	}
+
+	user := User{Nickname: "n", DisplayName: "Custom", Email: "A@X.COM"}
+	result, err := copier.CopyWithResult(&user, user, copier.Option{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"DisplayName", "Email"}; !reflect.DeepEqual(result.Changed, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Changed)
+	}
+}
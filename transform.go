@@ -0,0 +1,224 @@
+package copier
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// TransformFunc transforms a source value before it's copied into a field tagged with `copier:"transform=name"`
+type TransformFunc func(reflect.Value) (reflect.Value, error)
+
+var (
+	transformsLock sync.RWMutex
+	transforms     = map[string]TransformFunc{
+		"trim":   stringTransform(strings.TrimSpace),
+		"lower":  stringTransform(strings.ToLower),
+		"upper":  stringTransform(strings.ToUpper),
+		"redact": redact,
+	}
+)
+
+// RegisterTransform registers a transform usable in `copier:"transform=name"` tags, replacing any transform of the same name.
+// The built-in transforms are `trim`, `lower` and `upper` for strings, and `redact` replacing strings with `***`
+// and other values with their zero value.
+func RegisterTransform(name string, fn TransformFunc) {
+	transformsLock.Lock()
+	defer transformsLock.Unlock()
+	transforms[name] = fn
+}
+
+// applyTransforms transforms v with the transforms named names, in order.
+func applyTransforms(v reflect.Value, names []string) (reflect.Value, error) {
+	for _, name := range names {
+		transformsLock.RLock()
+		fn, ok := transforms[name]
+		transformsLock.RUnlock()
+		if !ok {
+			return v, fmt.Errorf("%w %q", ErrUnknownTransform, name)
+		}
+
+		transformed, err := fn(v)
+		if err != nil {
+			return v, fmt.Errorf("transform %s: %w", name, err)
+		}
+		if !transformed.IsValid() {
+			transformed = reflect.Zero(v.Type())
+		}
+		v = transformed
+	}
+	return v, nil
+}
+
+func transformError(err error, name string) error {
+	if _, ok := err.(*ConversionError); ok {
+		return withField(err, name)
+	}
+	return fmt.Errorf("field %s: %w", name, err)
+}
+
+// stringTransform transforms strings, pointers to strings and slices of strings with fn.
+func stringTransform(fn func(string) string) TransformFunc {
+	var transform TransformFunc
+	transform = func(v reflect.Value) (reflect.Value, error) {
+		switch v.Kind() {
+		case reflect.String:
+			return reflect.ValueOf(fn(v.String())).Convert(v.Type()), nil
+		case reflect.Ptr, reflect.Interface:
+			if v.IsNil() {
+				return v, nil
+			}
+			elem, err := transform(v.Elem())
+			if err != nil || v.Kind() == reflect.Interface {
+				return elem, err
+			}
+			ptr := reflect.New(elem.Type())
+			ptr.Elem().Set(elem)
+			return ptr, nil
+		case reflect.Slice, reflect.Array:
+			if v.Kind() == reflect.Slice && v.IsNil() {
+				return v, nil
+			}
+			transformed := reflect.New(v.Type()).Elem()
+			if v.Kind() == reflect.Slice {
+				transformed.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+			}
+			for i := 0; i < v.Len(); i++ {
+				elem, err := transform(v.Index(i))
+				if err != nil {
+					return v, err
+				}
+				transformed.Index(i).Set(elem)
+			}
+			return transformed, nil
+		}
+		return v, fmt.Errorf("%w transform of %v", ErrNotSupported, v.Type())
+	}
+	return transform
+}
+
+const redactMask = "***"
+
+func redact(v reflect.Value) (reflect.Value, error) {
+	if v.Kind() == reflect.String && v.Len() > 0 {
+		return reflect.ValueOf(redactMask).Convert(v.Type()), nil
+	}
+	return reflect.Zero(v.Type()), nil
+}
+
+// redactValue returns v with the fields tagged with `copier:"sensitive"` redacted, at any depth.
+// Values without sensitive fields are returned as is.
+func redactValue(v reflect.Value, mode RedactMode, redacted map[uintptr]reflect.Value) reflect.Value {
+	if !v.IsValid() || !hasSensitive(v.Type()) {
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		if r, ok := redacted[v.Pointer()]; ok {
+			return r
+		}
+		r := reflect.New(v.Type().Elem())
+		redacted[v.Pointer()] = r
+		r.Elem().Set(redactValue(v.Elem(), mode, redacted))
+		return r
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		r := reflect.New(v.Type()).Elem()
+		r.Set(redactValue(v.Elem(), mode, redacted))
+		return r
+	case reflect.Struct:
+		r := reflect.New(v.Type()).Elem()
+		r.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !r.Field(i).CanSet() {
+				continue
+			}
+			if isSensitive(field) {
+				if mode == RedactMask {
+					masked, _ := redact(v.Field(i))
+					r.Field(i).Set(masked)
+				} else {
+					r.Field(i).Set(reflect.Zero(field.Type))
+				}
+				continue
+			}
+			r.Field(i).Set(redactValue(v.Field(i), mode, redacted))
+		}
+		return r
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		r := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, key := range v.MapKeys() {
+			r.SetMapIndex(key, redactValue(v.MapIndex(key), mode, redacted))
+		}
+		return r
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return v
+		}
+		r := reflect.New(v.Type()).Elem()
+		if v.Kind() == reflect.Slice {
+			r.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+		}
+		for i := 0; i < v.Len(); i++ {
+			r.Index(i).Set(redactValue(v.Index(i), mode, redacted))
+		}
+		return r
+	}
+	return v
+}
+
+func isSensitive(field reflect.StructField) bool {
+	if tags := field.Tag.Get("copier"); tags != "" {
+		flg, _, _ := parseTags(tags)
+		return flg&tagSensitive != 0
+	}
+	return false
+}
+
+var sensitiveMap sync.Map
+
+// hasSensitive reports whether values of type t may hold fields tagged with `copier:"sensitive"`, as interfaces may.
+func hasSensitive(t reflect.Type) bool {
+	if cached, ok := sensitiveMap.Load(t); ok {
+		return cached.(bool)
+	}
+	found := findSensitive(t, map[reflect.Type]bool{})
+	sensitiveMap.Store(t, found)
+	return found
+}
+
+func findSensitive(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return findSensitive(t.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if isSensitive(field) || findSensitive(field.Type, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}